@@ -0,0 +1,294 @@
+package api
+
+// Values of the PlayerRelative feature layers.
+const (
+	PlayerRelativeNone    = 0
+	PlayerRelativeSelf    = 1
+	PlayerRelativeAlly    = 2
+	PlayerRelativeNeutral = 3
+	PlayerRelativeEnemy   = 4
+)
+
+// Values of the VisibilityMap feature layers.
+const (
+	VisibilityHidden     = 0
+	VisibilityFogged     = 1
+	VisibilityVisible    = 2
+	VisibilityFullHidden = 3
+)
+
+// layerBits returns the layer as bits or an empty image if the layer wasn't requested. Layers
+// with more bits per pixel are set wherever they aren't zero.
+func layerBits(img *ImageData) ImageDataBits {
+	if !validLayer(img) {
+		return ImageDataBits{}
+	}
+	if img.BitsPerPixel == 1 {
+		return img.Bits()
+	}
+	bits := NewImageDataBits(img.Size_.X, img.Size_.Y)
+	eachPixel(img, func(x, y, v int32) { bits.Set(x, y, v != 0) })
+	return bits
+}
+
+// layerBytes returns the layer as bytes or an empty image if the layer wasn't requested. Layers
+// with a different depth are converted, truncating values that don't fit.
+func layerBytes(img *ImageData) ImageDataBytes {
+	if !validLayer(img) {
+		return ImageDataBytes{}
+	}
+	if img.BitsPerPixel == 8 {
+		return img.Bytes()
+	}
+	bytes := NewImageDataBytes(img.Size_.X, img.Size_.Y)
+	eachPixel(img, func(x, y, v int32) { bytes.Set(x, y, byte(v)) })
+	return bytes
+}
+
+// layerInts returns the layer as int32s or an empty image if the layer wasn't requested. Layers
+// with a different depth are converted.
+func layerInts(img *ImageData) ImageDataInt32 {
+	if !validLayer(img) {
+		return ImageDataInt32{}
+	}
+	if img.BitsPerPixel == 32 {
+		return img.Ints()
+	}
+	ints := NewImageDataInts(img.Size_.X, img.Size_.Y)
+	eachPixel(img, func(x, y, v int32) { ints.Set(x, y, v) })
+	return ints
+}
+
+// validLayer returns true if the layer is present and has a supported depth and enough data.
+func validLayer(img *ImageData) bool {
+	if img == nil || img.Size_ == nil {
+		return false
+	}
+	switch img.BitsPerPixel {
+	case 1, 8, 32:
+		n := int64(img.Size_.X) * int64(img.Size_.Y) * int64(img.BitsPerPixel)
+		return img.Size_.X >= 0 && img.Size_.Y >= 0 && int64(len(img.Data))*8 >= n
+	}
+	return false
+}
+
+// eachPixel calls f with the value of every pixel of a valid layer.
+func eachPixel(img *ImageData, f func(x, y, v int32)) {
+	var get func(x, y int32) int32
+	switch img.BitsPerPixel {
+	case 1:
+		bits := img.Bits()
+		get = func(x, y int32) int32 {
+			if bits.Get(x, y) {
+				return 1
+			}
+			return 0
+		}
+	case 8:
+		bytes := img.Bytes()
+		get = func(x, y int32) int32 { return int32(bytes.Get(x, y)) }
+	case 32:
+		get = img.Ints().Get
+	}
+	for y := int32(0); y < img.Size_.Y; y++ {
+		for x := int32(0); x < img.Size_.X; x++ {
+			f(x, y, get(x, y))
+		}
+	}
+}
+
+// Screen feature layers. Each accessor decodes the layer using its bit depth and returns an
+// empty image (where Get always returns zero) if the layer isn't present.
+
+// HeightMapLayer returns the terrain height (0-255).
+func (f *FeatureLayers) HeightMapLayer() ImageDataBytes {
+	return layerBytes(f.GetHeightMap())
+}
+
+// VisibilityMapLayer returns the visibility (see the Visibility* constants).
+func (f *FeatureLayers) VisibilityMapLayer() ImageDataBytes {
+	return layerBytes(f.GetVisibilityMap())
+}
+
+// CreepLayer returns where there is creep.
+func (f *FeatureLayers) CreepLayer() ImageDataBits {
+	return layerBits(f.GetCreep())
+}
+
+// PowerLayer returns where there is psionic matrix power.
+func (f *FeatureLayers) PowerLayer() ImageDataBits {
+	return layerBits(f.GetPower())
+}
+
+// PlayerIDLayer returns the owning player ID of each unit.
+func (f *FeatureLayers) PlayerIDLayer() ImageDataBytes {
+	return layerBytes(f.GetPlayerId())
+}
+
+// UnitTypeLayer returns the UnitTypeID of each unit.
+func (f *FeatureLayers) UnitTypeLayer() ImageDataInt32 {
+	return layerInts(f.GetUnitType())
+}
+
+// SelectedLayer returns which units are selected.
+func (f *FeatureLayers) SelectedLayer() ImageDataBits {
+	return layerBits(f.GetSelected())
+}
+
+// UnitHitPointsLayer returns the hit points of each unit.
+func (f *FeatureLayers) UnitHitPointsLayer() ImageDataInt32 {
+	return layerInts(f.GetUnitHitPoints())
+}
+
+// UnitHitPointsRatioLayer returns the hit points of each unit as a fraction of 255.
+func (f *FeatureLayers) UnitHitPointsRatioLayer() ImageDataBytes {
+	return layerBytes(f.GetUnitHitPointsRatio())
+}
+
+// UnitEnergyLayer returns the energy of each unit.
+func (f *FeatureLayers) UnitEnergyLayer() ImageDataInt32 {
+	return layerInts(f.GetUnitEnergy())
+}
+
+// UnitEnergyRatioLayer returns the energy of each unit as a fraction of 255.
+func (f *FeatureLayers) UnitEnergyRatioLayer() ImageDataBytes {
+	return layerBytes(f.GetUnitEnergyRatio())
+}
+
+// UnitShieldsLayer returns the shields of each unit.
+func (f *FeatureLayers) UnitShieldsLayer() ImageDataInt32 {
+	return layerInts(f.GetUnitShields())
+}
+
+// UnitShieldsRatioLayer returns the shields of each unit as a fraction of 255.
+func (f *FeatureLayers) UnitShieldsRatioLayer() ImageDataBytes {
+	return layerBytes(f.GetUnitShieldsRatio())
+}
+
+// PlayerRelativeLayer returns the alliance of each unit (see the PlayerRelative* constants).
+func (f *FeatureLayers) PlayerRelativeLayer() ImageDataBytes {
+	return layerBytes(f.GetPlayerRelative())
+}
+
+// UnitDensityAALayer returns the anti-aliased unit density.
+func (f *FeatureLayers) UnitDensityAALayer() ImageDataBytes {
+	return layerBytes(f.GetUnitDensityAa())
+}
+
+// UnitDensityLayer returns the number of units in each pixel.
+func (f *FeatureLayers) UnitDensityLayer() ImageDataBytes {
+	return layerBytes(f.GetUnitDensity())
+}
+
+// EffectsLayer returns the EffectID of active effects.
+func (f *FeatureLayers) EffectsLayer() ImageDataBytes {
+	return layerBytes(f.GetEffects())
+}
+
+// HallucinationsLayer returns which units are hallucinations.
+func (f *FeatureLayers) HallucinationsLayer() ImageDataBits {
+	return layerBits(f.GetHallucinations())
+}
+
+// CloakedLayer returns which units are cloaked.
+func (f *FeatureLayers) CloakedLayer() ImageDataBits {
+	return layerBits(f.GetCloaked())
+}
+
+// BlipLayer returns which units are radar blips.
+func (f *FeatureLayers) BlipLayer() ImageDataBits {
+	return layerBits(f.GetBlip())
+}
+
+// BuffsLayer returns the BuffID of each unit.
+func (f *FeatureLayers) BuffsLayer() ImageDataInt32 {
+	return layerInts(f.GetBuffs())
+}
+
+// BuffDurationLayer returns the remaining buff duration as a fraction of 255.
+func (f *FeatureLayers) BuffDurationLayer() ImageDataBytes {
+	return layerBytes(f.GetBuffDuration())
+}
+
+// ActiveLayer returns which units are active (e.g. producing).
+func (f *FeatureLayers) ActiveLayer() ImageDataBits {
+	return layerBits(f.GetActive())
+}
+
+// BuildProgressLayer returns the build progress of each unit as a fraction of 255.
+func (f *FeatureLayers) BuildProgressLayer() ImageDataBytes {
+	return layerBytes(f.GetBuildProgress())
+}
+
+// BuildableLayer returns where structures can be placed.
+func (f *FeatureLayers) BuildableLayer() ImageDataBits {
+	return layerBits(f.GetBuildable())
+}
+
+// PathableLayer returns where ground units can move.
+func (f *FeatureLayers) PathableLayer() ImageDataBits {
+	return layerBits(f.GetPathable())
+}
+
+// PlaceholderLayer returns where queued structure placeholders are.
+func (f *FeatureLayers) PlaceholderLayer() ImageDataBits {
+	return layerBits(f.GetPlaceholder())
+}
+
+// Minimap feature layers. Each accessor decodes the layer using its bit depth and returns an
+// empty image (where Get always returns zero) if the layer isn't present.
+
+// HeightMapLayer returns the terrain height (0-255).
+func (f *FeatureLayersMinimap) HeightMapLayer() ImageDataBytes {
+	return layerBytes(f.GetHeightMap())
+}
+
+// VisibilityMapLayer returns the visibility (see the Visibility* constants).
+func (f *FeatureLayersMinimap) VisibilityMapLayer() ImageDataBytes {
+	return layerBytes(f.GetVisibilityMap())
+}
+
+// CreepLayer returns where there is creep.
+func (f *FeatureLayersMinimap) CreepLayer() ImageDataBits {
+	return layerBits(f.GetCreep())
+}
+
+// CameraLayer returns the area covered by the camera.
+func (f *FeatureLayersMinimap) CameraLayer() ImageDataBits {
+	return layerBits(f.GetCamera())
+}
+
+// PlayerIDLayer returns the owning player ID of each unit.
+func (f *FeatureLayersMinimap) PlayerIDLayer() ImageDataBytes {
+	return layerBytes(f.GetPlayerId())
+}
+
+// PlayerRelativeLayer returns the alliance of each unit (see the PlayerRelative* constants).
+func (f *FeatureLayersMinimap) PlayerRelativeLayer() ImageDataBytes {
+	return layerBytes(f.GetPlayerRelative())
+}
+
+// SelectedLayer returns which units are selected.
+func (f *FeatureLayersMinimap) SelectedLayer() ImageDataBits {
+	return layerBits(f.GetSelected())
+}
+
+// AlertsLayer returns where alerts are shown.
+func (f *FeatureLayersMinimap) AlertsLayer() ImageDataBytes {
+	return layerBytes(f.GetAlerts())
+}
+
+// BuildableLayer returns where structures can be placed.
+func (f *FeatureLayersMinimap) BuildableLayer() ImageDataBits {
+	return layerBits(f.GetBuildable())
+}
+
+// PathableLayer returns where ground units can move.
+func (f *FeatureLayersMinimap) PathableLayer() ImageDataBits {
+	return layerBits(f.GetPathable())
+}
+
+// UnitTypeLayer returns the UnitTypeID of each unit.
+func (f *FeatureLayersMinimap) UnitTypeLayer() ImageDataInt32 {
+	return layerInts(f.GetUnitType())
+}
@@ -0,0 +1,45 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+func TestFeatureLayers(t *testing.T) {
+	size := &api.Size2DI{X: 4, Y: 2}
+	f := &api.FeatureLayers{
+		Creep:          &api.ImageData{BitsPerPixel: 1, Size_: size, Data: []byte{0x81}},
+		HeightMap:      &api.ImageData{BitsPerPixel: 8, Size_: size, Data: []byte{0, 1, 2, 3, 4, 5, 6, 7}},
+		UnitType:       &api.ImageData{BitsPerPixel: 32, Size_: size, Data: make([]byte, 32)},
+		Pathable:       &api.ImageData{BitsPerPixel: 8, Size_: size, Data: []byte{0, 1, 0, 0, 0, 0, 0, 255}},
+		PlayerRelative: &api.ImageData{BitsPerPixel: 1, Size_: size, Data: []byte{0x80}},
+		Power:          &api.ImageData{BitsPerPixel: 1, Size_: size, Data: nil},
+	}
+	f.UnitType.Data[4*5] = 48
+
+	if creep := f.CreepLayer(); !creep.Get(0, 0) || creep.Get(1, 0) || !creep.Get(3, 1) {
+		t.Errorf("got creep %v %v %v", creep.Get(0, 0), creep.Get(1, 0), creep.Get(3, 1))
+	}
+	if h := f.HeightMapLayer(); h.Get(3, 1) != 7 || h.Width() != 4 || h.Height() != 2 {
+		t.Errorf("got height %v in a %vx%v image", h.Get(3, 1), h.Width(), h.Height())
+	}
+	if u := f.UnitTypeLayer(); u.Get(1, 1) != 48 || u.Get(0, 0) != 0 {
+		t.Errorf("got unit types %v and %v", u.Get(1, 1), u.Get(0, 0))
+	}
+
+	// Layers with an unexpected depth are converted instead of panicking
+	if p := f.PathableLayer(); p.Get(0, 0) || !p.Get(1, 0) || !p.Get(3, 1) {
+		t.Errorf("got pathable %v %v %v", p.Get(0, 0), p.Get(1, 0), p.Get(3, 1))
+	}
+	if r := f.PlayerRelativeLayer(); r.Get(0, 0) != 1 || r.Get(1, 0) != 0 {
+		t.Errorf("got player relative %v %v", r.Get(0, 0), r.Get(1, 0))
+	}
+
+	// Missing or truncated layers are empty
+	for name, img := range map[string]api.ImageDataBits{"power": f.PowerLayer(), "blip": f.BlipLayer()} {
+		if img.Width() != 0 || img.InBounds(0, 0) || img.Get(0, 0) {
+			t.Errorf("expected an empty %v layer", name)
+		}
+	}
+}
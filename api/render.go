@@ -0,0 +1,189 @@
+package api
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+// Colormap converts a byte value into a color.
+type Colormap func(v byte) color.Color
+
+// GrayColormap maps 0 to black and 255 to white.
+func GrayColormap(v byte) color.Color {
+	return color.Gray{Y: v}
+}
+
+// HeatColormap maps 0 to dark blue through green and yellow to 255 as red.
+func HeatColormap(v byte) color.Color {
+	// Piecewise linear blue -> cyan -> green -> yellow -> red
+	stops := [...]color.RGBA{
+		{0, 0, 128, 255},
+		{0, 192, 255, 255},
+		{0, 224, 64, 255},
+		{255, 224, 0, 255},
+		{224, 0, 0, 255},
+	}
+	pos := int(v) * (len(stops) - 1)
+	i, t := pos/255, pos%255
+	if i >= len(stops)-1 {
+		return stops[len(stops)-1]
+	}
+	a, b := stops[i], stops[i+1]
+	lerp := func(x, y uint8) uint8 {
+		return uint8((int(x)*(255-t) + int(y)*t) / 255)
+	}
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), 255}
+}
+
+// PaletteColormap maps each value to the palette entry with that index, values past the end of
+// the palette use the last entry. This is useful for categorical layers such as PlayerRelative.
+func PaletteColormap(palette color.Palette) Colormap {
+	return func(v byte) color.Color {
+		if int(v) >= len(palette) {
+			return palette[len(palette)-1]
+		}
+		return palette[v]
+	}
+}
+
+// PlayerRelativePalette colors the PlayerRelative* values (none, self, ally, neutral, enemy).
+var PlayerRelativePalette = color.Palette{
+	color.RGBA{0, 0, 0, 255},
+	color.RGBA{0, 160, 0, 255},
+	color.RGBA{0, 96, 224, 255},
+	color.RGBA{160, 160, 96, 255},
+	color.RGBA{224, 0, 0, 255},
+}
+
+// ToImage returns a paletted image using off for unset bits and on for set bits.
+func (img ImageDataBits) ToImage(off, on color.Color) *image.Paletted {
+	out := image.NewPaletted(image.Rect(0, 0, int(img.Width()), int(img.Height())), color.Palette{off, on})
+	for y := int32(0); y < img.Height(); y++ {
+		for x := int32(0); x < img.Width(); x++ {
+			if img.Get(x, y) {
+				out.SetColorIndex(int(x), int(y), 1)
+			}
+		}
+	}
+	return out
+}
+
+// ToGray returns a grayscale image of the bytes.
+func (img ImageDataBytes) ToGray() *image.Gray {
+	out := image.NewGray(image.Rect(0, 0, int(img.Width()), int(img.Height())))
+	for y := int32(0); y < img.Height(); y++ {
+		copy(out.Pix[int(y)*out.Stride:], img.data[img.offset(0, y):img.offset(0, y+1)])
+	}
+	return out
+}
+
+// ToImage returns an image with each byte mapped through the colormap.
+func (img ImageDataBytes) ToImage(cm Colormap) *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, int(img.Width()), int(img.Height())))
+	for y := int32(0); y < img.Height(); y++ {
+		for x := int32(0); x < img.Width(); x++ {
+			out.Set(int(x), int(y), cm(img.Get(x, y)))
+		}
+	}
+	return out
+}
+
+// ToImage returns an image with the values scaled from their min/max range to 0-255 and then
+// mapped through the colormap.
+func (img ImageDataInt32) ToImage(cm Colormap) *image.RGBA {
+	min, max := int32(0), int32(0)
+	for y := int32(0); y < img.Height(); y++ {
+		for x := int32(0); x < img.Width(); x++ {
+			v := img.Get(x, y)
+			if (x == 0 && y == 0) || v < min {
+				min = v
+			}
+			if (x == 0 && y == 0) || v > max {
+				max = v
+			}
+		}
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, int(img.Width()), int(img.Height())))
+	for y := int32(0); y < img.Height(); y++ {
+		for x := int32(0); x < img.Width(); x++ {
+			v := byte(0)
+			if max > min {
+				v = byte(int64(img.Get(x, y)-min) * 255 / int64(max-min))
+			}
+			out.Set(int(x), int(y), cm(v))
+		}
+	}
+	return out
+}
+
+// ToImage converts RGB render data (24 bits per pixel) into an image. Other pixel sizes are
+// converted using the typed versions with a grayscale colormap.
+func (img ImageData) ToImage() image.Image {
+	if img.Size_ == nil {
+		return image.NewRGBA(image.Rectangle{})
+	}
+	switch img.BitsPerPixel {
+	case 1:
+		return img.Bits().ToImage(color.Black, color.White)
+	case 8:
+		return img.Bytes().ToGray()
+	case 32:
+		return img.Ints().ToImage(GrayColormap)
+	}
+	img.assertBPP(24)
+
+	w, h := int(img.Size_.X), int(img.Size_.Y)
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < w*h && 3*i+2 < len(img.Data); i++ {
+		out.Pix[4*i+0] = img.Data[3*i+0]
+		out.Pix[4*i+1] = img.Data[3*i+1]
+		out.Pix[4*i+2] = img.Data[3*i+2]
+		out.Pix[4*i+3] = 255
+	}
+	return out
+}
+
+// ScaleImage returns a copy of src enlarged by an integer factor using nearest neighbor
+// sampling so each grid cell stays a crisp square.
+func ScaleImage(src image.Image, factor int) image.Image {
+	if factor <= 1 {
+		return src
+	}
+	b := src.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx()*factor, b.Dy()*factor))
+	for y := 0; y < out.Rect.Dy(); y++ {
+		for x := 0; x < out.Rect.Dx(); x++ {
+			out.Set(x, y, src.At(b.Min.X+x/factor, b.Min.Y+y/factor))
+		}
+	}
+	return out
+}
+
+// FlipImage returns a vertically mirrored copy of src. World grids such as StartRaw are indexed
+// by world coordinates (y increases to the north), so flipping them puts north at the top.
+func FlipImage(src image.Image) image.Image {
+	b := src.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			out.Set(x, b.Dy()-1-y, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return out
+}
+
+// WritePNG encodes img as a PNG file at path.
+func WritePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
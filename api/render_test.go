@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+func TestRenderToImage(t *testing.T) {
+	rgb := api.ImageData{BitsPerPixel: 24, Size_: &api.Size2DI{X: 2, Y: 1}, Data: []byte{1, 2, 3, 4, 5, 6}}
+	img := rgb.ToImage()
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 1 {
+		t.Fatalf("got bounds %v", b)
+	}
+	if c := img.At(1, 0); c != (color.RGBA{4, 5, 6, 255}) {
+		t.Errorf("got color %v", c)
+	}
+
+	// Other depths are converted to grayscale
+	bits := api.ImageData{BitsPerPixel: 1, Size_: &api.Size2DI{X: 2, Y: 1}, Data: []byte{0x80}}
+	if r, _, _, _ := bits.ToImage().At(0, 0).RGBA(); r != 0xffff {
+		t.Errorf("expected a set bit to be white, got %v", r)
+	}
+	ints := api.ImageData{BitsPerPixel: 32, Size_: &api.Size2DI{X: 2, Y: 1}, Data: []byte{0, 0, 0, 10, 0, 0, 0, 20}}
+	if c := ints.ToImage().At(1, 0); c != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("expected the max value to be white, got %v", c)
+	}
+	if b := (api.ImageData{}).ToImage().Bounds(); !b.Empty() {
+		t.Errorf("expected an empty image, got %v", b)
+	}
+}
+
+func TestScaleAndFlipImage(t *testing.T) {
+	img := api.ImageData{BitsPerPixel: 24, Size_: &api.Size2DI{X: 1, Y: 2}, Data: []byte{255, 0, 0, 0, 0, 255}}.ToImage()
+
+	scaled := api.ScaleImage(img, 3)
+	if b := scaled.Bounds(); b.Dx() != 3 || b.Dy() != 6 {
+		t.Fatalf("got bounds %v", b)
+	}
+	if c := scaled.At(2, 2); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("got color %v at the bottom right of the first cell", c)
+	}
+	if c := scaled.At(0, 3); c != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("got color %v at the top left of the second cell", c)
+	}
+
+	flipped := api.FlipImage(img)
+	if c := flipped.At(0, 0); c != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("expected the last row first, got %v", c)
+	}
+}
+
+func TestColormaps(t *testing.T) {
+	if c := api.HeatColormap(0); c != (color.RGBA{0, 0, 128, 255}) {
+		t.Errorf("got %v for 0", c)
+	}
+	if c := api.HeatColormap(255); c != (color.RGBA{224, 0, 0, 255}) {
+		t.Errorf("got %v for 255", c)
+	}
+	cm := api.PaletteColormap(api.PlayerRelativePalette)
+	if c := cm(api.PlayerRelativeEnemy); c != api.PlayerRelativePalette[4] {
+		t.Errorf("got %v for an enemy", c)
+	}
+	if c := cm(200); c != api.PlayerRelativePalette[4] {
+		t.Errorf("expected values past the end to use the last color, got %v", c)
+	}
+}
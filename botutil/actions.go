@@ -2,7 +2,6 @@ package botutil
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/client"
@@ -16,6 +15,7 @@ type Actions struct {
 	actions      []*api.Action
 	prevActions  []*api.Action
 	errorHandler ActionErrorHandler
+	failed       []ActionErrorHandler
 }
 
 // ActionErrorHandler is the handler function type for action errors.
@@ -34,6 +34,12 @@ func (a *Actions) OnActionError(handler ActionErrorHandler) {
 	a.errorHandler = handler
 }
 
+// onActionFailed registers an internal handler that is called for errors in addition to the
+// user's error handler.
+func (a *Actions) onActionFailed(handler ActionErrorHandler) {
+	a.failed = append(a.failed, handler)
+}
+
 // LogActionErrors registers an error handler that will log the error.
 func (a *Actions) LogActionErrors() {
 	a.OnActionError(func(action *api.Action, r api.ActionResult) {
@@ -55,9 +61,9 @@ func (a *Actions) LogActionErrors() {
 			} else {
 				dst = raw.UnitCommand.GetTargetWorldSpacePos().String()
 			}
-			log.Printf("Action %v: %v %v > %v", r, src, abil, dst)
+			a.info.Logger().Warn("Action failed", "result", r, "units", src, "ability", abil, "target", dst)
 		default:
-			log.Printf("Action %v: %v", r, action)
+			a.info.Logger().Warn("Action failed", "result", r, "action", action)
 		}
 	})
 }
@@ -79,11 +85,15 @@ func (a *Actions) Send() {
 	}
 
 	results := a.info.SendActions(a.actions)
-	if a.errorHandler != nil {
-		for i, r := range results {
-			if r != api.ActionResult_Success {
-				a.errorHandler(a.actions[i], r)
-			}
+	for i, r := range results {
+		if r == api.ActionResult_Success {
+			continue
+		}
+		if a.errorHandler != nil {
+			a.errorHandler(a.actions[i], r)
+		}
+		for _, f := range a.failed {
+			f(a.actions[i], r)
 		}
 	}
 	a.prevActions = a.actions
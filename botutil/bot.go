@@ -2,7 +2,6 @@ package botutil
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/chippydip/go-sc2ai/client"
@@ -16,6 +15,8 @@ type Bot struct {
 
 	*Player
 	*UnitContext
+	*Events
+	*EnemyMemory
 	*Actions
 	*Builder
 }
@@ -27,11 +28,13 @@ func NewBot(info client.AgentInfo) *Bot {
 	bot.Player = NewPlayer(info)
 	bot.Actions = NewActions(info)
 	bot.UnitContext = NewUnitContext(info, bot)
+	bot.Events = NewEvents(info, bot.UnitContext)
+	bot.EnemyMemory = NewEnemyMemory(info, bot.UnitContext, bot.Events)
 	bot.Builder = NewBuilder(info, bot.Player, bot.UnitContext)
+	bot.Player.watchReservations(bot.Events, bot.Actions)
 
 	update := func() {
 		bot.GameLoop = bot.Observation().GetObservation().GetGameLoop()
-		log.SetPrefix(fmt.Sprintf("[%v] ", bot.GameLoop))
 
 		if bot.GameLoop == 224 {
 			bot.checkVersion()
@@ -45,7 +48,7 @@ func NewBot(info client.AgentInfo) *Bot {
 
 func (bot *Bot) checkVersion() {
 	if c, ok := bot.AgentInfo.(*client.Client); !ok {
-		log.Print("Skipping version check") // Should only happen when AgentInfo is mocked
+		bot.Logger().Info("Skipping version check") // Should only happen when AgentInfo is mocked
 	} else {
 		_ = c
 		// Check the game version, this should be less important but still worth reporting
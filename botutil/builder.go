@@ -1,7 +1,7 @@
 package botutil
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/client"
@@ -13,12 +13,13 @@ import (
 type Builder struct {
 	player *Player
 	units  *UnitContext
+	tech   *TechTree
 	used   map[api.UnitTag]bool
 }
 
 // NewBuilder creates a new Builder and registers it to fix FoodUsed rounding for zerg.
 func NewBuilder(info client.AgentInfo, player *Player, units *UnitContext) *Builder {
-	b := &Builder{player, units, map[api.UnitTag]bool{}}
+	b := &Builder{player, units, NewTechTree(info.Data()), map[api.UnitTag]bool{}}
 
 	update := func() {
 		// This is only really an issue for zerg
@@ -52,12 +53,17 @@ func (b *Builder) BuildUnit(producer api.UnitTypeID, train api.AbilityID) bool {
 }
 
 // BuildUnits commands available producers to use the train ability to build/morph/train/warp count units.
-// Returns the number of units actually ordered based on producer, food, mineral, and vespene availability.
+// Returns the number of units actually ordered based on tech, producer, food, mineral, and vespene availability.
 func (b *Builder) BuildUnits(producer api.UnitTypeID, train api.AbilityID, count int) int {
 	if count <= 0 {
 		return 0
 	}
 
+	req, attached := b.tech.Requirement(b.tech.Produces(train))
+	if req != unit.Invalid && !attached && !b.HasTech(req) {
+		return 0
+	}
+
 	cost := b.ProductionCost(producer, train)
 
 	// Find all available producers
@@ -68,7 +74,7 @@ func (b *Builder) BuildUnits(producer api.UnitTypeID, train api.AbilityID, count
 			return false
 		}
 
-		if u.BuildProgress < 1 || (len(u.Orders) > 0 && u.IsStructure()) || b.used[u.Tag] {
+		if !b.isIdleProducer(u) || (attached && !b.hasAddon(u, req)) {
 			return false
 		}
 
@@ -86,13 +92,14 @@ func (b *Builder) BuildUnits(producer api.UnitTypeID, train api.AbilityID, count
 // TODO: BuildUnitsWithAddon
 
 // BuildUnitAt commands an available producer to use the train ability to build/morph/train/warp a unit at the given location.
-// If the food, mineral, and vespene requirements are not met or no producer was found it does nothing and returns false.
+// If the tech, food, mineral, and vespene requirements are not met or no producer was found it does nothing and returns false.
 func (b *Builder) BuildUnitAt(producer api.UnitTypeID, train api.AbilityID, pos api.Point2D) bool {
-	// Check if we can afford one
-	cost := b.ProductionCost(producer, train)
-	if !b.player.CanAfford(cost) {
+	// Check tech requirements and that we can afford one
+	check := b.CanBuild(producer, train)
+	if !check.OK() {
 		return false
 	}
+	cost := check.Cost
 
 	// Find the closest available producer
 	u := b.getNearestBuilder(producer, pos)
@@ -103,18 +110,19 @@ func (b *Builder) BuildUnitAt(producer api.UnitTypeID, train api.AbilityID, pos
 	// Produce the unit and adjust available resources
 	u.OrderPos(train, pos)
 	b.used[u.Tag] = true
-	b.player.Spend(cost)
+	b.spend(u, train, cost)
 	return true
 }
 
 // BuildUnitOn commands an available producer to use the train ability to build/morph/train/warp a unit on the given target.
-// If the food, mineral, and vespene requirements are not met or no producer was found it does nothing and returns false.
+// If the tech, food, mineral, and vespene requirements are not met or no producer was found it does nothing and returns false.
 func (b *Builder) BuildUnitOn(producer api.UnitTypeID, train api.AbilityID, target Unit) bool {
-	// Check if we can afford one
-	cost := b.ProductionCost(producer, train)
-	if !b.player.CanAfford(cost) {
+	// Check tech requirements and that we can afford one
+	check := b.CanBuild(producer, train)
+	if !check.OK() {
 		return false
 	}
+	cost := check.Cost
 
 	// Find the closest available producer
 	u := b.getNearestBuilder(producer, target.Pos2D())
@@ -125,15 +133,12 @@ func (b *Builder) BuildUnitOn(producer api.UnitTypeID, train api.AbilityID, targ
 	// Produce the unit and adjust available resources
 	u.OrderTarget(train, target)
 	b.used[u.Tag] = true
-	b.player.Spend(cost)
+	b.spend(u, train, cost)
 	return true
 }
 
 func (b *Builder) getNearestBuilder(producer api.UnitTypeID, pos api.Point2D) Unit {
-	builders := b.units.Self[producer].Drop(func(u Unit) bool {
-		return u.BuildProgress < 1 || (len(u.Orders) > 0 && u.IsStructure()) || b.used[u.Tag]
-	})
-	return builders.ClosestTo(pos)
+	return b.units.Self[producer].Choose(b.isIdleProducer).ClosestTo(pos)
 }
 
 // ProductionCost computes the Cost for producerType to train once.
@@ -144,9 +149,9 @@ func (b *Builder) ProductionCost(producerType api.UnitTypeID, train api.AbilityI
 	}
 
 	// Get the unit that will be built/trained
-	targetType := ability.Produces(train)
+	targetType := b.tech.Produces(train)
 	if targetType == unit.Invalid {
-		log.Panicf("%v does not produce a unit", train)
+		panic(fmt.Sprintf("%v does not produce a unit", train))
 	}
 
 	producer := b.units.data[producerType]
@@ -167,7 +172,7 @@ func (b *Builder) ProductionCost(producerType api.UnitTypeID, train api.AbilityI
 	// Double-check that we have an integer food cost now (do we need to handle anything other than zerglings?)
 	foodMult := food * float32(multiplier)
 	if float32(uint32(foodMult)) != foodMult {
-		log.Panicf("unexpected FoodRequirement: %v -> %v x%v for %v", producer.FoodRequired, target.FoodRequired, multiplier, targetType)
+		panic(fmt.Sprintf("unexpected FoodRequirement: %v -> %v x%v for %v", producer.FoodRequired, target.FoodRequired, multiplier, targetType))
 	}
 
 	// Per-build cost for this unit
@@ -227,7 +232,7 @@ func (u Unit) BuildUnitAt(train api.AbilityID, pos api.Point2D) bool {
 	// Produce the unit and adjust available resources
 	u.OrderPos(train, pos)
 	b.used[u.Tag] = true
-	b.player.Spend(cost)
+	b.spend(u, train, cost)
 	return true
 }
 
@@ -250,7 +255,7 @@ func (u Unit) BuildUnitOn(train api.AbilityID, target Unit) bool {
 	// Produce the unit and adjust available resources
 	u.OrderTarget(train, target)
 	b.used[u.Tag] = true
-	b.player.Spend(cost)
+	b.spend(u, train, cost)
 	return true
 }
 
@@ -0,0 +1,422 @@
+package botutil
+
+import (
+	"fmt"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+	"github.com/chippydip/go-sc2ai/enums/unit"
+	"github.com/chippydip/go-sc2ai/enums/upgrade"
+)
+
+// LoopsPerSecond is the number of game loops per second of game time at "faster" speed.
+const LoopsPerSecond = 22.4
+
+// BuildAction is the kind of action performed by a BuildStep.
+type BuildAction string
+
+// Supported build actions.
+const (
+	ActionBuild    BuildAction = "build"    // build a structure with a worker
+	ActionTrain    BuildAction = "train"    // train or morph a unit from a producer
+	ActionResearch BuildAction = "research" // research an upgrade
+	ActionScout    BuildAction = "scout"    // send a worker to the enemy start location
+)
+
+// Placement picks the location for a new structure. It returns false if no location is available.
+type Placement func(bot *Bot, structure api.UnitTypeID, build api.AbilityID) (api.Point2D, bool)
+
+// BuildStep is one entry in a build order. It is started once all of its trigger conditions are
+// met, zero values are ignored.
+type BuildStep struct {
+	// Trigger
+	Supply    uint32         // at least this much supply used
+	GameLoop  uint32         // at least this game loop
+	UnitType  api.UnitTypeID // at least UnitCount of our own units of this type
+	UnitCount int
+	Minerals  uint32 // at least this many minerals
+	Vespene   uint32 // at least this much vespene
+
+	// Action
+	Action   BuildAction
+	Target   api.UnitTypeID // unit or structure to produce
+	Upgrade  api.UpgradeID  // upgrade to research
+	Producer api.UnitTypeID // optional, derived from the available abilities if not set
+	Ability  api.AbilityID  // optional, derived from Target or Upgrade if not set
+	Count    int            // number of units to train (defaults to 1)
+
+	// Placement for structures (optional). PlacementName is looked up in the registered
+	// placements if Placement is nil, otherwise structures are placed near the main base.
+	Placement     Placement
+	PlacementName string
+}
+
+func (s *BuildStep) String() string {
+	switch {
+	case s.Target != unit.Invalid:
+		return fmt.Sprintf("%v %v", s.Action, unit.String(s.Target))
+	case s.Upgrade != 0:
+		return fmt.Sprintf("%v %v", s.Action, upgrade.String(s.Upgrade))
+	}
+	return string(s.Action)
+}
+
+// BuildOrderStatus reports the progress of a build order.
+type BuildOrderStatus struct {
+	Step   int    // index of the next step to run, len(Steps) once the build order is done
+	Name   string // description of the next step
+	Reason string // why the next step is blocked (empty if it isn't)
+}
+
+// BuildOrder runs a list of steps one after another using the Builder.
+type BuildOrder struct {
+	bot        *Bot
+	steps      []BuildStep
+	next       int
+	ordered    int
+	reason     string
+	waiting    bool   // the current step was issued and is waiting for its orders to be seen
+	issuedLoop uint32 // game loop the current step was issued
+	units      int    // units of the current step's target when it was issued
+	orders     int    // orders using the current step's ability when it was issued
+	scout      api.UnitTag
+	placements map[string]Placement
+}
+
+// NewBuildOrder creates a build order for the given steps.
+func NewBuildOrder(bot *Bot, steps []BuildStep) *BuildOrder {
+	return &BuildOrder{
+		bot:        bot,
+		steps:      steps,
+		placements: map[string]Placement{},
+	}
+}
+
+// RegisterPlacement makes a placement available to steps by name (e.g. for loaded build orders).
+func (o *BuildOrder) RegisterPlacement(name string, placement Placement) {
+	o.placements[name] = placement
+}
+
+// Steps returns the build order steps.
+func (o *BuildOrder) Steps() []BuildStep {
+	return o.steps
+}
+
+// Done returns true once every step has been completed.
+func (o *BuildOrder) Done() bool {
+	return o.next >= len(o.steps)
+}
+
+// Scout returns the tag of the worker sent by the last scout step.
+func (o *BuildOrder) Scout() api.UnitTag {
+	return o.scout
+}
+
+// Status returns the current step and why it's blocked.
+func (o *BuildOrder) Status() BuildOrderStatus {
+	status := BuildOrderStatus{Step: o.next, Reason: o.reason}
+	if !o.Done() {
+		status.Name = o.steps[o.next].String()
+	}
+	return status
+}
+
+// Execute runs as many steps as possible. It should be called once per game step. Steps that
+// produce something are kept until the structure is seen or the unit or upgrade is in production,
+// and are issued again if their orders are lost.
+func (o *BuildOrder) Execute() {
+	for !o.Done() {
+		step := &o.steps[o.next]
+		reason := ""
+		if o.waiting {
+			reason = o.confirm(step)
+		} else if reason = o.triggered(step); reason == "" {
+			reason = o.issue(step)
+		}
+		if reason != "" {
+			if reason != o.reason {
+				o.bot.Logger().Debug("Build order blocked", "step", o.next, "name", step.String(), "reason", reason)
+			}
+			o.reason = reason
+			return
+		}
+		o.next, o.ordered, o.reason, o.waiting = o.next+1, 0, "", false
+	}
+}
+
+// issue runs the step and starts waiting for its orders to be seen if it produces something.
+func (o *BuildOrder) issue(step *BuildStep) string {
+	first := o.ordered == 0
+	reason := o.run(step)
+
+	// New orders only show up in the next observation, so the current one is the baseline
+	if first && (reason == "" || o.ordered > 0) {
+		o.units, o.orders = o.bot.Self.Count(step.Target), o.ordersFor(step.Ability)
+	}
+	if reason != "" {
+		return reason
+	}
+	if step.Action == ActionScout || (step.Action == ActionResearch && o.bot.HasUpgrade(step.Upgrade)) {
+		return ""
+	}
+	o.waiting, o.issuedLoop = true, o.bot.GameLoop
+	return fmt.Sprintf("waiting for %v", step)
+}
+
+// confirm returns an empty string once the current step's result is seen. If its orders were
+// lost (e.g. the build location was blocked) the step is issued again.
+func (o *BuildOrder) confirm(step *BuildStep) string {
+	bot := o.bot
+	units, orders := bot.Self.Count(step.Target), o.ordersFor(step.Ability)
+	switch {
+	case bot.GameLoop <= o.issuedLoop: // not observed yet
+	case step.Action == ActionResearch && bot.HasUpgrade(step.Upgrade):
+		return ""
+	case step.Target != unit.Invalid && units > o.units:
+		return ""
+	case step.Action != ActionBuild && units+orders > o.units+o.orders:
+		return "" // in production
+	case orders <= o.orders:
+		bot.Logger().Debug("Build order retrying", "step", o.next, "name", step.String())
+		o.waiting, o.ordered = false, 0
+		return o.issue(step)
+	}
+	return fmt.Sprintf("waiting for %v", step)
+}
+
+// ordersFor counts the orders of our own units using the ability.
+func (o *BuildOrder) ordersFor(abil api.AbilityID) int {
+	n, abil := 0, ability.Remap(abil)
+	o.bot.Self.All().Each(func(u Unit) {
+		for _, order := range u.Orders {
+			if ability.Remap(order.AbilityId) == abil {
+				n++
+			}
+		}
+	})
+	return n
+}
+
+// triggered returns the first unmet trigger condition or an empty string.
+func (o *BuildOrder) triggered(step *BuildStep) string {
+	bot := o.bot
+	switch {
+	case bot.FoodUsed < step.Supply:
+		return fmt.Sprintf("waiting for %v supply", step.Supply)
+	case bot.GameLoop < step.GameLoop:
+		return fmt.Sprintf("waiting for %.0fs", float64(step.GameLoop)/LoopsPerSecond)
+	case step.UnitType != unit.Invalid && bot.Self.Count(step.UnitType) < step.UnitCount:
+		return fmt.Sprintf("waiting for %v %v", step.UnitCount, unit.String(step.UnitType))
+	case bot.Minerals < step.Minerals:
+		return fmt.Sprintf("waiting for %v minerals", step.Minerals)
+	case bot.Vespene < step.Vespene:
+		return fmt.Sprintf("waiting for %v vespene", step.Vespene)
+	}
+	return ""
+}
+
+// run performs the step's action and returns why it couldn't be completed (if it wasn't).
+func (o *BuildOrder) run(step *BuildStep) string {
+	switch step.Action {
+	case ActionBuild:
+		return o.build(step)
+	case ActionTrain:
+		return o.train(step)
+	case ActionResearch:
+		return o.research(step)
+	case ActionScout:
+		return o.sendScout()
+	}
+	return fmt.Sprintf("unknown action %q", step.Action)
+}
+
+func (o *BuildOrder) build(step *BuildStep) string {
+	bot := o.bot
+	if step.Ability == 0 {
+		step.Ability = bot.tech.data(step.Target).GetAbilityId()
+	}
+	if step.Ability == 0 {
+		return fmt.Sprintf("no build ability for %v", unit.String(step.Target))
+	}
+	if step.Producer == unit.Invalid {
+		step.Producer = o.workerType()
+	}
+	if check := bot.CanBuild(step.Producer, step.Ability); !check.OK() {
+		return check.String()
+	}
+
+	// Gas buildings target a geyser instead of a location
+	if abilities := bot.Data().GetAbilities(); int(step.Ability) < len(abilities) &&
+		abilities[step.Ability].GetTarget() == api.AbilityData_Unit {
+		geyser := o.freeGeyser()
+		if geyser.IsNil() || !bot.BuildUnitOn(step.Producer, step.Ability, geyser) {
+			return "no free geyser"
+		}
+		return ""
+	}
+
+	placement := step.Placement
+	if placement == nil && step.PlacementName != "" {
+		if placement = o.placements[step.PlacementName]; placement == nil {
+			return fmt.Sprintf("unknown placement %q", step.PlacementName)
+		}
+	}
+	if placement == nil {
+		placement = NearTownHall
+	}
+	pos, ok := placement(bot, step.Target, step.Ability)
+	if !ok {
+		return "no placement"
+	}
+	if !bot.BuildUnitAt(step.Producer, step.Ability, pos) {
+		return "no builder"
+	}
+	return ""
+}
+
+func (o *BuildOrder) train(step *BuildStep) string {
+	bot := o.bot
+	if step.Ability == 0 {
+		step.Ability = bot.tech.data(step.Target).GetAbilityId()
+	}
+	if step.Ability == 0 {
+		return fmt.Sprintf("no train ability for %v", unit.String(step.Target))
+	}
+	if step.Producer == unit.Invalid {
+		if step.Producer = o.producerType(step.Ability); step.Producer == unit.Invalid {
+			if next := bot.NextToUnlock(step.Target); next != unit.Invalid {
+				return fmt.Sprintf("%v: %v (%v)", unit.String(step.Target), BuildMissingStructure, unit.String(next))
+			}
+			return fmt.Sprintf("no producer for %v", unit.String(step.Target))
+		}
+	}
+
+	count := step.Count
+	if count <= 0 {
+		count = 1
+	}
+	o.ordered += bot.BuildUnits(step.Producer, step.Ability, count-o.ordered)
+	if o.ordered < count {
+		return bot.CanBuild(step.Producer, step.Ability).String()
+	}
+	return ""
+}
+
+func (o *BuildOrder) research(step *BuildStep) string {
+	bot := o.bot
+	if bot.HasUpgrade(step.Upgrade) {
+		return "" // already done
+	}
+	if step.Ability == 0 {
+		if upgrades := bot.Data().GetUpgrades(); int(step.Upgrade) < len(upgrades) {
+			step.Ability = upgrades[step.Upgrade].GetAbilityId()
+		}
+	}
+
+	cost := bot.UpgradeCost(step.Upgrade)
+	if !bot.CanAfford(cost) {
+		return fmt.Sprintf("%v: needs %v minerals, %v vespene", step, cost.Minerals, cost.Vespene)
+	}
+
+	producers := bot.Self.All()
+	if step.Producer != unit.Invalid {
+		producers = bot.Self[step.Producer]
+	}
+	u := producers.CanOrder(step.Ability).Choose(bot.isIdleProducer).First()
+	if u.IsNil() {
+		return fmt.Sprintf("%v: %v", step, BuildNoIdleProducer)
+	}
+	u.Order(step.Ability)
+	bot.used[u.Tag] = true
+	bot.Spend(cost)
+	return ""
+}
+
+func (o *BuildOrder) sendScout() string {
+	bot := o.bot
+	var target api.Point2D
+	for _, loc := range bot.GameInfo().GetStartRaw().GetStartLocations() {
+		target = *loc // only enemy start locations are included
+	}
+
+	worker := bot.Self[o.workerType()].Choose(bot.isIdleProducer).ClosestTo(target)
+	if worker.IsNil() {
+		return "no scout"
+	}
+	worker.MoveTo(target, 0)
+	bot.used[worker.Tag] = true
+	o.scout = worker.Tag
+	return ""
+}
+
+// producerType returns the type of the first unit that can use the ability right now.
+func (o *BuildOrder) producerType(ability api.AbilityID) api.UnitTypeID {
+	return o.bot.Self.All().CanOrder(ability).First().GetUnitType()
+}
+
+func (o *BuildOrder) workerType() api.UnitTypeID {
+	switch o.bot.RaceActual {
+	case api.Race_Protoss:
+		return unit.Protoss_Probe
+	case api.Race_Zerg:
+		return unit.Zerg_Drone
+	}
+	return unit.Terran_SCV
+}
+
+// freeGeyser returns the closest geyser to one of our town halls without a gas building on it.
+func (o *BuildOrder) freeGeyser() Unit {
+	bot := o.bot
+	taken := map[api.Point2D]bool{}
+	bot.AllUnits().Choose(Unit.IsGasBuilding).Each(func(u Unit) {
+		taken[u.Pos2D()] = true
+	})
+
+	best, bestDist := Unit{}, float32(0)
+	bot.Self.All().Choose(Unit.IsTownHall).IsBuilt().Each(func(th Unit) {
+		bot.Neutral.Vespene().CloserThan(10, th.Pos2D()).Each(func(g Unit) {
+			if dist := g.Pos2D().Distance2(th.Pos2D()); !taken[g.Pos2D()] && (best.IsNil() || dist < bestDist) {
+				best, bestDist = g, dist
+			}
+		})
+	})
+	return best
+}
+
+// NearTownHall places structures around the first town hall, away from its mineral line, using
+// placement queries to find a valid location.
+func NearTownHall(bot *Bot, structure api.UnitTypeID, build api.AbilityID) (api.Point2D, bool) {
+	th := bot.Self.All().Choose(Unit.IsTownHall).First()
+	if th.IsNil() {
+		return api.Point2D{}, false
+	}
+	center, away := th.Pos2D(), api.Vec2D{}
+	if minerals := bot.Neutral.Minerals().CloserThan(12, center); minerals.Len() > 0 {
+		away = minerals.Center().DirTo(center)
+	}
+
+	var candidates []api.Point2D
+	for r := float32(6); r <= 14; r += 2 {
+		for x := -r; x <= r; x += 2 {
+			for y := -r; y <= r; y += 2 {
+				if x != -r && x != r && y != -r && y != r {
+					continue // only the ring at this radius
+				}
+				pt := api.Point2D{X: center.X + x + away.X*4, Y: center.Y + y + away.Y*4}
+				candidates = append(candidates, pt)
+			}
+		}
+	}
+
+	query := make([]*api.RequestQueryBuildingPlacement, len(candidates))
+	for i := range candidates {
+		query[i] = &api.RequestQueryBuildingPlacement{AbilityId: build, TargetPos: &candidates[i]}
+	}
+	resp := bot.Query(api.RequestQuery{Placements: query, IgnoreResourceRequirements: true})
+	for i, r := range resp.GetPlacements() {
+		if r.GetResult() == api.ActionResult_Success && i < len(candidates) {
+			return candidates[i], true
+		}
+	}
+	return api.Point2D{}, false
+}
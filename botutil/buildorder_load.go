@@ -0,0 +1,188 @@
+package botutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+	"github.com/chippydip/go-sc2ai/enums/unit"
+	"github.com/chippydip/go-sc2ai/enums/upgrade"
+)
+
+// LoadBuildOrder reads build steps from a file. Files starting with '[' are parsed as JSON,
+// anything else as text (see ParseBuildOrder).
+func LoadBuildOrder(path string) ([]BuildStep, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return ParseBuildOrderJSON(data)
+	}
+	return ParseBuildOrder(bytes.NewReader(data))
+}
+
+// ParseBuildOrder parses one build step per line. Each line has key=value trigger and option
+// fields plus an action followed by its target name, e.g.:
+//
+//	# comments and blank lines are ignored
+//	supply=14 build SupplyDepot
+//	supply=16 minerals=150 build Barracks placement=proxy
+//	unit=Barracks train Marine count=2
+//	time=2:30 research Stimpack
+//	supply=17 scout
+//
+// Trigger keys are supply, time (seconds or m:ss), unit and units (count, default 1), minerals
+// and vespene. Option keys are producer, ability, count and placement. Names may omit the race
+// prefix used by the enums package.
+func ParseBuildOrder(r io.Reader) ([]BuildStep, error) {
+	var steps []BuildStep
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := map[string]string{}
+		for _, token := range strings.Fields(text) {
+			if i := strings.IndexByte(token, '='); i >= 0 {
+				fields[token[:i]] = token[i+1:]
+			} else if _, ok := fields["action"]; !ok {
+				fields["action"] = token
+			} else if _, ok := fields["target"]; !ok {
+				fields["target"] = token
+			} else {
+				return nil, fmt.Errorf("line %v: unexpected %q", line, token)
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		step, err := parseBuildStep(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, scanner.Err()
+}
+
+// ParseBuildOrderJSON parses a JSON array of objects using the same keys as ParseBuildOrder
+// plus "action" and "target", e.g. [{"supply": 14, "action": "build", "target": "SupplyDepot"}].
+func ParseBuildOrderJSON(data []byte) ([]BuildStep, error) {
+	var raw []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // keep large numbers from being formatted as floats
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	steps := make([]BuildStep, 0, len(raw))
+	for i, obj := range raw {
+		fields := map[string]string{}
+		for k, v := range obj {
+			fields[k] = fmt.Sprint(v)
+		}
+		step, err := parseBuildStep(fields)
+		if err != nil {
+			return nil, fmt.Errorf("step %v: %v", i, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func parseBuildStep(fields map[string]string) (BuildStep, error) {
+	step := BuildStep{Action: BuildAction(fields["action"])}
+	target := fields["target"]
+
+	var err error
+	for k, v := range fields {
+		switch k {
+		case "action", "target":
+		case "supply":
+			step.Supply, err = parseUint(v)
+		case "time":
+			step.GameLoop, err = parseTime(v)
+		case "unit":
+			step.UnitType, err = parseUnit(v)
+			if step.UnitCount == 0 {
+				step.UnitCount = 1
+			}
+		case "units":
+			step.UnitCount, err = strconv.Atoi(v)
+		case "minerals":
+			step.Minerals, err = parseUint(v)
+		case "vespene":
+			step.Vespene, err = parseUint(v)
+		case "producer":
+			step.Producer, err = parseUnit(v)
+		case "ability":
+			if step.Ability, _ = ability.FromString(v); step.Ability == 0 {
+				err = fmt.Errorf("unknown ability %q", v)
+			}
+		case "count":
+			step.Count, err = strconv.Atoi(v)
+		case "placement":
+			step.PlacementName = v
+		default:
+			err = fmt.Errorf("unknown key %q", k)
+		}
+		if err != nil {
+			return step, err
+		}
+	}
+
+	switch step.Action {
+	case ActionBuild, ActionTrain:
+		step.Target, err = parseUnit(target)
+	case ActionResearch:
+		if step.Upgrade, _ = upgrade.FromString(target); step.Upgrade == 0 {
+			err = fmt.Errorf("unknown upgrade %q", target)
+		}
+	case ActionScout:
+	default:
+		err = fmt.Errorf("unknown action %q", step.Action)
+	}
+	return step, err
+}
+
+func parseUint(s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return uint32(v), nil
+}
+
+// parseTime converts seconds or m:ss of game time into a game loop.
+func parseTime(s string) (uint32, error) {
+	minutes, seconds := "0", s
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		minutes, seconds = s[:i], s[i+1:]
+	}
+	m, err1 := strconv.ParseFloat(minutes, 64)
+	sec, err2 := strconv.ParseFloat(seconds, 64)
+	if err1 != nil || err2 != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return uint32((m*60 + sec) * LoopsPerSecond), nil
+}
+
+// parseUnit finds a unit type by name, with or without its race prefix.
+func parseUnit(name string) (api.UnitTypeID, error) {
+	for _, prefix := range []string{"", "Terran_", "Protoss_", "Zerg_", "Neutral_"} {
+		if id, ok := unit.FromString(prefix + name); ok && id != unit.Invalid {
+			return id, nil
+		}
+	}
+	return unit.Invalid, fmt.Errorf("unknown unit %q", name)
+}
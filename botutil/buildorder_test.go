@@ -0,0 +1,183 @@
+package botutil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+	"github.com/chippydip/go-sc2ai/enums/upgrade"
+)
+
+func TestParseBuildOrder(t *testing.T) {
+	text := `
+# opener
+supply=14 build SupplyDepot
+supply=16 minerals=150 build Barracks placement=proxy
+unit=Barracks units=2 train Marine count=2 # after both rax
+time=2:30 research Stimpack
+scout
+`
+	steps, err := botutil.ParseBuildOrder(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	json := `[
+		{"supply": 14, "action": "build", "target": "SupplyDepot"},
+		{"supply": 16, "minerals": 150, "action": "build", "target": "Barracks", "placement": "proxy"},
+		{"unit": "Barracks", "units": 2, "action": "train", "target": "Marine", "count": 2},
+		{"time": "2:30", "action": "research", "target": "Stimpack"},
+		{"action": "scout"}
+	]`
+	jsonSteps, err := botutil.ParseBuildOrderJSON([]byte(json))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []botutil.BuildStep{
+		{Supply: 14, Action: botutil.ActionBuild, Target: terran.SupplyDepot},
+		{Supply: 16, Minerals: 150, Action: botutil.ActionBuild, Target: terran.Barracks, PlacementName: "proxy"},
+		{UnitType: terran.Barracks, UnitCount: 2, Action: botutil.ActionTrain, Target: terran.Marine, Count: 2},
+		{GameLoop: 3360, Action: botutil.ActionResearch, Upgrade: upgrade.Stimpack},
+		{Action: botutil.ActionScout},
+	}
+	for _, got := range [][]botutil.BuildStep{steps, jsonSteps} {
+		if len(got) != len(expected) {
+			t.Fatalf("got %v steps, expected %v", len(got), len(expected))
+		}
+		for i := range expected {
+			if got[i].String() != expected[i].String() || got[i].Supply != expected[i].Supply ||
+				got[i].GameLoop != expected[i].GameLoop || got[i].UnitType != expected[i].UnitType ||
+				got[i].UnitCount != expected[i].UnitCount || got[i].Minerals != expected[i].Minerals ||
+				got[i].Count != expected[i].Count || got[i].PlacementName != expected[i].PlacementName {
+				t.Errorf("step %v: got %+v, expected %+v", i, got[i], expected[i])
+			}
+		}
+	}
+
+	for _, bad := range []string{"build Nonsense", "supply=x scout", "fly away", "foo=1 scout", "build Barracks Factory", "supply=1.5 scout"} {
+		if _, err := botutil.ParseBuildOrder(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestParseBuildOrderJSONIntegers(t *testing.T) {
+	steps, err := botutil.ParseBuildOrderJSON([]byte(`[{"minerals": 1000000, "action": "scout"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps[0].Minerals != 1000000 {
+		t.Errorf("got %v minerals, expected 1000000", steps[0].Minerals)
+	}
+}
+
+func TestExecuteBuildOrder(t *testing.T) {
+	structure := []api.Attribute{api.Attribute_Structure}
+	units := make([]*api.UnitTypeData, 1000)
+	units[terran.SCV] = &api.UnitTypeData{UnitId: terran.SCV, Available: true, MineralCost: 50, FoodRequired: 1, AbilityId: ability.Train_SCV}
+	units[terran.CommandCenter] = &api.UnitTypeData{UnitId: terran.CommandCenter, Available: true, Attributes: structure}
+	units[terran.SupplyDepot] = &api.UnitTypeData{UnitId: terran.SupplyDepot, Available: true, Attributes: structure,
+		MineralCost: 100, AbilityId: ability.Build_SupplyDepot}
+	units[terran.Barracks] = &api.UnitTypeData{UnitId: terran.Barracks, Available: true, Attributes: structure,
+		MineralCost: 150, AbilityId: ability.Build_Barracks, TechRequirement: terran.SupplyDepot}
+	units[terran.Marine] = &api.UnitTypeData{UnitId: terran.Marine, Available: true, MineralCost: 50, FoodRequired: 1,
+		AbilityId: ability.Train_Marine}
+	upgrades := make([]*api.UpgradeData, 100)
+	upgrades[upgrade.Stimpack] = &api.UpgradeData{UpgradeId: upgrade.Stimpack, MineralCost: 100, VespeneCost: 100,
+		AbilityId: ability.Research_Stimpack}
+
+	i := &fakeInfo{
+		data:     &api.ResponseData{Units: units, Upgrades: upgrades},
+		gameInfo: &api.ResponseGameInfo{StartRaw: &api.StartRaw{StartLocations: []*api.Point2D{{X: 100, Y: 100}}}},
+		player:   &api.PlayerCommon{Minerals: 1000, Vespene: 1000, FoodCap: 23, FoodUsed: 12},
+		abilities: map[api.UnitTag][]api.AbilityID{
+			2: {ability.Build_SupplyDepot, ability.Build_Barracks},
+			3: {ability.Train_Marine, ability.Research_Stimpack},
+		},
+	}
+	cc := &api.Unit{Tag: 1, UnitType: terran.CommandCenter, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}}
+	scv := &api.Unit{Tag: 2, UnitType: terran.SCV, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{X: 5}}
+	rax := &api.Unit{Tag: 3, UnitType: terran.Barracks, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{X: 20}}
+	depot := &api.Unit{Tag: 4, UnitType: terran.SupplyDepot, Alliance: api.Alliance_Self, BuildProgress: 0.5, Pos: &api.Point{X: 10}}
+	marine := &api.Unit{Tag: 5, UnitType: terran.Marine, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{X: 20}}
+	i.step(nil, cc, scv)
+	bot := botutil.NewBot(i)
+
+	fixed := func(bot *botutil.Bot, structure api.UnitTypeID, build api.AbilityID) (api.Point2D, bool) {
+		return api.Point2D{X: 10, Y: 10}, true
+	}
+	o := botutil.NewBuildOrder(bot, []botutil.BuildStep{
+		{Action: botutil.ActionBuild, Target: terran.SupplyDepot, Placement: fixed},
+		{Action: botutil.ActionBuild, Target: terran.Barracks, Placement: fixed},
+		{Action: botutil.ActionTrain, Target: terran.Marine},
+		{Action: botutil.ActionResearch, Upgrade: upgrade.Stimpack, Producer: terran.Barracks},
+		{Action: botutil.ActionScout},
+	})
+	order := func(ability api.AbilityID) []*api.UnitOrder {
+		return []*api.UnitOrder{{AbilityId: ability}}
+	}
+	run := func(units ...*api.Unit) {
+		i.gameLoop++
+		i.step(nil, units...)
+		o.Execute()
+		bot.Actions.Send()
+	}
+	expect := func(step int, reason string, abilities ...api.AbilityID) {
+		t.Helper()
+		if s := o.Status(); s.Step != step || !strings.Contains(s.Reason, reason) {
+			t.Errorf("got step %v (%v), expected %v (%v)", s.Step, s.Reason, step, reason)
+		}
+		sent := bot.Actions.PrevActions()
+		if len(abilities) == 0 {
+			sent = nil // PrevActions isn't cleared when nothing was sent
+		}
+		if len(sent) != len(abilities) {
+			t.Fatalf("got %v actions, expected %v", len(sent), len(abilities))
+		}
+		for j, a := range sent {
+			if got := a.GetActionRaw().GetUnitCommand().GetAbilityId(); got != abilities[j] {
+				t.Errorf("got action %v, expected %v", got, abilities[j])
+			}
+		}
+	}
+
+	run(cc, scv)
+	expect(0, "waiting for build Terran_SupplyDepot", ability.Build_SupplyDepot)
+
+	// The order was lost before the depot was started, so it's given again
+	run(cc, scv)
+	expect(0, "waiting for build Terran_SupplyDepot", ability.Build_SupplyDepot)
+	scv.Orders = order(ability.Build_SupplyDepot)
+	run(cc, scv)
+	expect(0, "waiting for build Terran_SupplyDepot")
+
+	// Once the depot is seen the Barracks waits for it to finish
+	scv.Orders = nil
+	run(cc, scv, depot)
+	expect(1, "missing structure (Terran_SupplyDepot)")
+
+	depot.BuildProgress = 1
+	run(cc, scv, depot)
+	expect(1, "waiting for build Terran_Barracks", ability.Build_Barracks)
+
+	run(cc, scv, depot, rax)
+	expect(2, "waiting for train Terran_Marine", ability.Train_Marine)
+
+	// The Marine is in production but the Barracks is busy until it's done
+	rax.Orders = order(ability.Train_Marine)
+	run(cc, scv, depot, rax)
+	expect(3, "no idle producer")
+
+	rax.Orders = nil
+	run(cc, scv, depot, rax, marine)
+	expect(3, "waiting for research Stimpack", ability.Research_Stimpack)
+
+	rax.Orders = order(ability.Research_Stimpack)
+	run(cc, scv, depot, rax, marine)
+	if !o.Done() || o.Scout() != scv.Tag {
+		t.Errorf("expected the build order to be done and scouting with %v, got %+v and %v", scv.Tag, o.Status(), o.Scout())
+	}
+}
@@ -0,0 +1,208 @@
+package botutil
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// normalLoopsPerSecond converts UnitTypeData speeds and cooldowns, which are given in "normal"
+// game speed seconds, into game loops.
+const normalLoopsPerSecond = 16
+
+// CombatOutcome is the predicted result of an engagement from the first side's point of view.
+type CombatOutcome int
+
+// CombatOutcome values.
+const (
+	CombatDraw CombatOutcome = iota // both sides survive (or neither does)
+	CombatWin
+	CombatLoss
+)
+
+func (o CombatOutcome) String() string {
+	switch o {
+	case CombatDraw:
+		return "draw"
+	case CombatWin:
+		return "win"
+	case CombatLoss:
+		return "loss"
+	}
+	return fmt.Sprintf("CombatOutcome(%d)", int(o))
+}
+
+// CombatResult is the estimate produced by a CombatSimulator.
+type CombatResult struct {
+	Outcome      CombatOutcome
+	Loops        uint32  // game loops until one side was destroyed (or the time limit)
+	Ours, Theirs Units   // surviving units of each side
+	OursHealth   float32 // remaining health plus shields of each side
+	TheirsHealth float32
+}
+
+// Seconds returns the simulated duration in game seconds.
+func (r CombatResult) Seconds() float32 {
+	return float32(r.Loops) / LoopsPerSecond
+}
+
+// CombatSimulator estimates the outcome of a fight between two groups of units. Units close the
+// distance to the nearest enemy they can hit and then trade shots using the weapon, armor, shield
+// and upgrade values from their UnitTypeData. Splash, spells, healing and regeneration are not
+// modeled, so the result is an estimate rather than a replay of the real fight.
+type CombatSimulator struct {
+	MaxLoops  uint32 // give up and report a draw after this many loops (default 60 seconds)
+	StepLoops uint32 // simulation resolution (default 2 loops)
+	InRange   bool   // ignore positions and assume every unit starts in range
+}
+
+// SimulateCombat runs the default CombatSimulator.
+func SimulateCombat(ours, theirs Units) CombatResult {
+	return CombatSimulator{}.Simulate(ours, theirs)
+}
+
+// Simulate estimates the outcome of ours fighting theirs. It only uses the units and their type
+// data so it can be run on hypothetical or recorded units.
+func (s CombatSimulator) Simulate(ours, theirs Units) CombatResult {
+	maxLoops, step := s.MaxLoops, s.StepLoops
+	if maxLoops == 0 {
+		maxLoops = uint32(60 * LoopsPerSecond)
+	}
+	if step == 0 {
+		step = 2
+	}
+
+	sides := [2][]*simUnit{newSimUnits(ours), newSimUnits(theirs)}
+	loops := uint32(0)
+	for ; loops < maxLoops && alive(sides[0]) && alive(sides[1]); loops += step {
+		// Units killed during a step still get to act so neither side strikes first
+		var acting [2][]*simUnit
+		for i := range sides {
+			for _, u := range sides[i] {
+				if u.alive() {
+					acting[i] = append(acting[i], u)
+				}
+			}
+		}
+		acted := false
+		for i := range acting {
+			for _, u := range acting[i] {
+				if u.act(sides[1-i], float32(step), s.InRange) {
+					acted = true
+				}
+			}
+		}
+		if !acted {
+			break // nobody can reach anything
+		}
+	}
+
+	result := CombatResult{Loops: loops}
+	result.Ours, result.OursHealth = survivors(sides[0])
+	result.Theirs, result.TheirsHealth = survivors(sides[1])
+	switch {
+	case result.Ours.Len() > 0 && result.Theirs.Len() == 0:
+		result.Outcome = CombatWin
+	case result.Ours.Len() == 0 && result.Theirs.Len() > 0:
+		result.Outcome = CombatLoss
+	}
+	return result
+}
+
+// simUnit is the mutable state of a unit during a simulation.
+type simUnit struct {
+	unit     Unit
+	levels   UpgradeLevels
+	pos      api.Point2D
+	vitals   vitals
+	cooldown float32 // loops until the next attack
+	target   *simUnit
+}
+
+func newSimUnits(units Units) []*simUnit {
+	var sim []*simUnit
+	units.Each(func(u Unit) {
+		sim = append(sim, &simUnit{
+			unit:     u,
+			levels:   u.UpgradeLevels(),
+			pos:      u.Pos2D(),
+			vitals:   vitals{u.Health, u.Shield},
+			cooldown: u.WeaponCooldown,
+		})
+	})
+	return sim
+}
+
+func (u *simUnit) alive() bool {
+	return u.vitals.health > 0
+}
+
+// act attacks or moves towards the current target. It returns false if the unit can't do either.
+func (u *simUnit) act(enemies []*simUnit, step float32, inRange bool) bool {
+	if u.target == nil || !u.target.alive() {
+		u.target = u.closestTarget(enemies)
+		if u.target == nil {
+			return false
+		}
+	}
+	weapon := u.unit.weaponAgainst(u.target.unit)
+
+	gap := u.pos.Distance(u.target.pos) - (u.unit.Radius + u.target.unit.Radius + weapon.Range)
+	if gap > 0 && !inRange {
+		speed := u.unit.MovementSpeed / normalLoopsPerSecond * step
+		if speed <= 0 {
+			u.target = nil // can't reach it, try another next step
+			return u.cooldown > 0
+		}
+		if speed > gap {
+			speed = gap
+		}
+		u.pos = u.pos.Offset(u.target.pos, speed)
+		u.cooldown -= step
+		return true
+	}
+
+	if u.cooldown -= step; u.cooldown <= 0 {
+		damage := u.unit.hitDamage(weapon, u.target.unit, u.levels)
+		u.target.vitals.attack(damage, weapon, u.target.unit, u.target.levels)
+		u.cooldown += weapon.Speed * normalLoopsPerSecond
+	}
+	return true
+}
+
+// closestTarget returns the closest living enemy the unit has a weapon for.
+func (u *simUnit) closestTarget(enemies []*simUnit) *simUnit {
+	var best *simUnit
+	bestDist := float32(math.Inf(1))
+	for _, e := range enemies {
+		if !e.alive() || !e.unit.CanBeTargeted() || u.unit.weaponAgainst(e.unit) == nil {
+			continue
+		}
+		if d := u.pos.Distance2(e.pos); d < bestDist {
+			best, bestDist = e, d
+		}
+	}
+	return best
+}
+
+func alive(units []*simUnit) bool {
+	for _, u := range units {
+		if u.alive() {
+			return true
+		}
+	}
+	return false
+}
+
+func survivors(units []*simUnit) (Units, float32) {
+	var left []Unit
+	health := float32(0)
+	for _, u := range units {
+		if u.alive() {
+			left = append(left, u.unit)
+			health += u.vitals.health + u.vitals.shields
+		}
+	}
+	return NewUnits(left), health
+}
@@ -0,0 +1,145 @@
+package botutil_test
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/protoss"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+	"github.com/chippydip/go-sc2ai/enums/upgrade"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+// combatData holds weapon values as reported by the game's ResponseData.
+var combatData = func() *api.ResponseData {
+	units := make([]*api.UnitTypeData, 1000)
+	light := []api.Attribute{api.Attribute_Light, api.Attribute_Biological}
+	armored := []api.Attribute{api.Attribute_Armored, api.Attribute_Mechanical}
+	massive := []api.Attribute{api.Attribute_Armored, api.Attribute_Biological, api.Attribute_Massive}
+	units[terran.Marine] = &api.UnitTypeData{UnitId: terran.Marine, Race: api.Race_Terran, MovementSpeed: 2.25, Attributes: light,
+		Weapons: []*api.Weapon{{Type: api.Weapon_Any, Damage: 6, Attacks: 1, Range: 5, Speed: 0.8608}}}
+	units[terran.Marauder] = &api.UnitTypeData{UnitId: terran.Marauder, Race: api.Race_Terran, MovementSpeed: 2.25, Armor: 1,
+		Attributes: []api.Attribute{api.Attribute_Armored, api.Attribute_Biological},
+		Weapons: []*api.Weapon{{Type: api.Weapon_Ground, Damage: 10, Attacks: 1, Range: 6, Speed: 1.5,
+			DamageBonus: []*api.DamageBonus{{Attribute: api.Attribute_Armored, Bonus: 10}}}}}
+	units[zerg.Zergling] = &api.UnitTypeData{UnitId: zerg.Zergling, Race: api.Race_Zerg, MovementSpeed: 2.95, Attributes: light,
+		Weapons: []*api.Weapon{{Type: api.Weapon_Ground, Damage: 5, Attacks: 1, Range: 0.1, Speed: 0.696}}}
+	units[zerg.Mutalisk] = &api.UnitTypeData{UnitId: zerg.Mutalisk, Race: api.Race_Zerg, MovementSpeed: 4, Attributes: light,
+		Weapons: []*api.Weapon{{Type: api.Weapon_Any, Damage: 9, Attacks: 1, Range: 3, Speed: 1.5246}}}
+	units[protoss.Stalker] = &api.UnitTypeData{UnitId: protoss.Stalker, Race: api.Race_Protoss, MovementSpeed: 2.9531, Armor: 1, Attributes: armored,
+		Weapons: []*api.Weapon{{Type: api.Weapon_Any, Damage: 13, Attacks: 1, Range: 6, Speed: 1.87,
+			DamageBonus: []*api.DamageBonus{{Attribute: api.Attribute_Armored, Bonus: 5}}}}}
+	units[zerg.Hydralisk] = &api.UnitTypeData{UnitId: zerg.Hydralisk, Race: api.Race_Zerg, MovementSpeed: 3.15, Attributes: light,
+		Weapons: []*api.Weapon{{Type: api.Weapon_Any, Damage: 12, Attacks: 1, Range: 5, Speed: 0.59}}}
+	units[zerg.Ultralisk] = &api.UnitTypeData{UnitId: zerg.Ultralisk, Race: api.Race_Zerg, MovementSpeed: 4.13, Armor: 2, Attributes: massive,
+		Weapons: []*api.Weapon{{Type: api.Weapon_Ground, Damage: 35, Attacks: 1, Range: 1, Speed: 0.861}}}
+	return &api.ResponseData{Units: units}
+}()
+
+type combatUnits struct {
+	tag      api.UnitTag
+	units    []*api.Unit
+	upgrades []api.UpgradeID
+}
+
+func (c *combatUnits) add(alliance api.Alliance, unitType api.UnitTypeID, x float32, count int) {
+	for i := 0; i < count; i++ {
+		c.tag++
+		u := &api.Unit{Tag: c.tag, UnitType: unitType, Alliance: alliance, Pos: &api.Point{X: x, Y: float32(i)},
+			Radius: 0.375, BuildProgress: 1, DisplayType: api.DisplayType_Visible}
+		switch unitType {
+		case terran.Marine:
+			u.Health = 45
+		case terran.Marauder:
+			u.Health = 125
+		case zerg.Zergling:
+			u.Health = 35
+		case zerg.Mutalisk:
+			u.Health, u.IsFlying = 120, true
+		case protoss.Stalker:
+			u.Health, u.Shield = 80, 80
+		case zerg.Hydralisk:
+			u.Health = 90
+		case zerg.Ultralisk:
+			u.Health = 500
+		}
+		c.units = append(c.units, u)
+	}
+}
+
+func (c *combatUnits) context() *botutil.UnitContext {
+	i := &fakeInfo{data: combatData, upgrades: c.upgrades}
+	i.step(nil, c.units...)
+	return botutil.NewUnitContext(i, nil)
+}
+
+func (c *combatUnits) simulate() botutil.CombatResult {
+	all := c.context().AllUnits()
+	ours := all.Choose(func(u botutil.Unit) bool { return u.Alliance == api.Alliance_Self })
+	theirs := all.Choose(func(u botutil.Unit) bool { return u.Alliance == api.Alliance_Enemy })
+	return botutil.SimulateCombat(ours, theirs)
+}
+
+func TestSimulateCombat(t *testing.T) {
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 10)
+	c.add(api.Alliance_Enemy, zerg.Zergling, 8, 4)
+	r := c.simulate()
+	if r.Outcome != botutil.CombatWin || r.Theirs.Len() != 0 || r.Ours.Len() == 0 {
+		t.Errorf("expected 10 marines to beat 4 zerglings, got %v with %v/%v left", r.Outcome, r.Ours.Len(), r.Theirs.Len())
+	}
+	if r.Loops == 0 || r.Seconds() > 10 {
+		t.Errorf("unexpected time to kill %v", r.Seconds())
+	}
+
+	c = &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 2)
+	c.add(api.Alliance_Enemy, zerg.Zergling, 3, 12)
+	if r := c.simulate(); r.Outcome != botutil.CombatLoss || r.Ours.Len() != 0 {
+		t.Errorf("expected 2 marines to lose to 12 zerglings, got %v", r.Outcome)
+	}
+}
+
+func TestSimulateCombatAirGround(t *testing.T) {
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, zerg.Zergling, 0, 20)
+	c.add(api.Alliance_Enemy, zerg.Mutalisk, 2, 1)
+	r := c.simulate()
+	if r.Outcome != botutil.CombatDraw || r.Theirs.Len() != 1 || r.TheirsHealth != 120 {
+		t.Errorf("expected zerglings to be unable to hit a mutalisk, got %v (%v)", r.Outcome, r.TheirsHealth)
+	}
+}
+
+func TestSimulateCombatBonusesAndUpgrades(t *testing.T) {
+	// Bonus damage against armored decides this fight
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marauder, 0, 1)
+	c.add(api.Alliance_Enemy, protoss.Stalker, 6, 1)
+	if r := c.simulate(); r.Outcome != botutil.CombatWin {
+		t.Errorf("expected a marauder to beat a stalker, got %v", r.Outcome)
+	}
+
+	// Even fights are decided by upgrades
+	c = &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 5)
+	c.add(api.Alliance_Enemy, terran.Marine, 5, 5)
+	if r := c.simulate(); r.Outcome != botutil.CombatDraw {
+		t.Errorf("expected a draw, got %v", r.Outcome)
+	}
+
+	for _, u := range c.units {
+		if u.Alliance == api.Alliance_Enemy {
+			u.AttackUpgradeLevel = 1
+		}
+	}
+	if r := c.simulate(); r.Outcome != botutil.CombatLoss {
+		t.Errorf("expected upgraded marines to win, got %v", r.Outcome)
+	}
+
+	// Our own upgrades come from HasUpgrade
+	c.upgrades = []api.UpgradeID{upgrade.TerranInfantryArmorsLevel1, upgrade.TerranInfantryArmorsLevel2}
+	if r := c.simulate(); r.Outcome != botutil.CombatWin {
+		t.Errorf("expected armored marines to win, got %v", r.Outcome)
+	}
+}
@@ -0,0 +1,344 @@
+package botutil
+
+import (
+	"math"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/enums/protoss"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+	"github.com/chippydip/go-sc2ai/enums/upgrade"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+// UpgradeLevels are the weapon, armor and shield upgrade levels that apply to a unit.
+type UpgradeLevels struct {
+	Attack, Armor, Shield int32
+}
+
+// UpgradeEstimate returns the upgrade levels that apply to an enemy unit.
+type UpgradeEstimate func(u Unit) UpgradeLevels
+
+// ObservedUpgrades uses the upgrade levels reported for the unit in the observation.
+func ObservedUpgrades(u Unit) UpgradeLevels {
+	return UpgradeLevels{u.AttackUpgradeLevel, u.ArmorUpgradeLevel, u.ShieldUpgradeLevel}
+}
+
+// UpgradeLevels returns the upgrades that apply to the unit. Our own levels are taken from
+// HasUpgrade, everyone else's from the context's EnemyUpgrades estimate.
+func (u Unit) UpgradeLevels() UpgradeLevels {
+	if u.Unit == nil {
+		return UpgradeLevels{}
+	}
+	if u.Alliance == api.Alliance_Self && u.ctx != nil && u.ctx.info != nil {
+		weapons, armor, shields := u.upgradeLines()
+		return UpgradeLevels{
+			Attack: u.ctx.upgradeLevel(weapons...),
+			Armor:  u.ctx.upgradeLevel(armor...),
+			Shield: u.ctx.upgradeLevel(shields),
+		}
+	}
+	if u.ctx != nil && u.ctx.EnemyUpgrades != nil {
+		return u.ctx.EnemyUpgrades(u)
+	}
+	return ObservedUpgrades(u)
+}
+
+// upgradeLines returns the level 1 upgrades for the unit's weapons, armor and shields.
+func (u Unit) upgradeLines() (weapons, armor []api.UpgradeID, shields api.UpgradeID) {
+	if u.IsStructure() {
+		if u.Race == api.Race_Protoss {
+			shields = upgrade.ProtossShieldsLevel1
+		}
+		return
+	}
+
+	switch u.Race {
+	case api.Race_Terran:
+		switch {
+		case u.HasAttribute(api.Attribute_Biological):
+			weapons = []api.UpgradeID{upgrade.TerranInfantryWeaponsLevel1}
+			armor = []api.UpgradeID{upgrade.TerranInfantryArmorsLevel1}
+		case u.IsFlying:
+			weapons = []api.UpgradeID{upgrade.TerranShipWeaponsLevel1, upgrade.TerranVehicleAndShipWeaponsLevel1}
+			armor = []api.UpgradeID{upgrade.TerranShipArmorsLevel1, upgrade.TerranVehicleAndShipArmorsLevel1}
+		default:
+			weapons = []api.UpgradeID{upgrade.TerranVehicleWeaponsLevel1, upgrade.TerranVehicleAndShipWeaponsLevel1}
+			armor = []api.UpgradeID{upgrade.TerranVehicleArmorsLevel1, upgrade.TerranVehicleAndShipArmorsLevel1}
+		}
+	case api.Race_Zerg:
+		switch {
+		case u.IsFlying:
+			weapons = []api.UpgradeID{upgrade.ZergFlyerWeaponsLevel1}
+			armor = []api.UpgradeID{upgrade.ZergFlyerArmorsLevel1}
+		case u.GroundWeaponRange() <= 1:
+			weapons = []api.UpgradeID{upgrade.ZergMeleeWeaponsLevel1}
+			armor = []api.UpgradeID{upgrade.ZergGroundArmorsLevel1}
+		default:
+			weapons = []api.UpgradeID{upgrade.ZergMissileWeaponsLevel1}
+			armor = []api.UpgradeID{upgrade.ZergGroundArmorsLevel1}
+		}
+	case api.Race_Protoss:
+		shields = upgrade.ProtossShieldsLevel1
+		if u.IsFlying {
+			weapons = []api.UpgradeID{upgrade.ProtossAirWeaponsLevel1}
+			armor = []api.UpgradeID{upgrade.ProtossAirArmorsLevel1}
+		} else {
+			weapons = []api.UpgradeID{upgrade.ProtossGroundWeaponsLevel1}
+			armor = []api.UpgradeID{upgrade.ProtossGroundArmorsLevel1}
+		}
+	}
+	return
+}
+
+// upgradeLevel returns the highest level researched in any of the given upgrade lines. Levels 2
+// and 3 immediately follow level 1.
+func (ctx *UnitContext) upgradeLevel(level1 ...api.UpgradeID) int32 {
+	best := int32(0)
+	for _, id := range level1 {
+		if id == 0 {
+			continue
+		}
+		for level := int32(3); level > best; level-- {
+			if ctx.info.HasUpgrade(id + api.UpgradeID(level-1)) {
+				best = level
+				break
+			}
+		}
+	}
+	return best
+}
+
+// GroundWeaponRange returns the range of the unit's longest ranged ground weapon, or -1.
+func (u Unit) GroundWeaponRange() float32 {
+	return u.weaponRange(api.Weapon_Ground)
+}
+
+// AirWeaponRange returns the range of the unit's longest ranged air weapon, or -1.
+func (u Unit) AirWeaponRange() float32 {
+	return u.weaponRange(api.Weapon_Air)
+}
+
+func (u Unit) weaponRange(weaponType api.Weapon_TargetType) float32 {
+	maxRange := float32(-1)
+	for _, w := range u.Weapons {
+		if w != nil && (w.Type == weaponType || w.Type == api.Weapon_Any) && w.Range > maxRange {
+			maxRange = w.Range
+		}
+	}
+	return maxRange
+}
+
+// GroundDPS returns the damage per second the unit can do to ground targets, including its
+// upgrades but not bonuses or the target's armor.
+func (u Unit) GroundDPS() float32 {
+	return u.weaponDPS(api.Weapon_Ground)
+}
+
+// AirDPS returns the damage per second the unit can do to air targets, including its upgrades
+// but not bonuses or the target's armor.
+func (u Unit) AirDPS() float32 {
+	return u.weaponDPS(api.Weapon_Air)
+}
+
+func (u Unit) weaponDPS(weaponType api.Weapon_TargetType) float32 {
+	if u.Unit == nil {
+		return 0
+	}
+	levels := u.UpgradeLevels()
+	best := float32(0)
+	for _, w := range u.Weapons {
+		if w == nil || w.Speed <= 0 || (w.Type != weaponType && w.Type != api.Weapon_Any) {
+			continue
+		}
+		damage := w.Damage + u.weaponUpgrade(w).damage*float32(levels.Attack)
+		if dps := damage * float32(hitsPerAttack(w)) / cooldownSeconds(w); dps > best {
+			best = dps
+		}
+	}
+	return best
+}
+
+// DamageAgainst returns the damage a single attack does to the target in its current state,
+// including attribute bonuses, the number of hits, armor, shields and both players' upgrades.
+// It returns 0 if the unit can't attack the target.
+func (u Unit) DamageAgainst(target Unit) float32 {
+	w := u.weaponAgainst(target)
+	if w == nil {
+		return 0
+	}
+	v := vitals{target.Health, target.Shield}
+	return v.attack(u.hitDamage(w, target, u.UpgradeLevels()), w, target, target.UpgradeLevels())
+}
+
+// DPSAgainst returns the damage per game second (at "faster" speed) the unit does to the target
+// after armor (or shield upgrades while it has shields). Unlike DamageAgainst it isn't limited by
+// the target's remaining health.
+func (u Unit) DPSAgainst(target Unit) float32 {
+	w := u.weaponAgainst(target)
+	if w == nil {
+		return 0
+	}
+	levels := target.UpgradeLevels()
+	armor := target.Armor + float32(levels.Armor)
+	if target.Shield > 0 {
+		armor = float32(levels.Shield)
+	}
+	damage := float32(math.Max(float64(u.hitDamage(w, target, u.UpgradeLevels())-armor), minDamage))
+	return damage * float32(hitsPerAttack(w)) / cooldownSeconds(w)
+}
+
+// HitsToKill returns the number of attacks needed to kill the target from its current health and
+// shields, or -1 if the unit can't damage it.
+func (u Unit) HitsToKill(target Unit) int {
+	w := u.weaponAgainst(target)
+	if w == nil || target.Unit == nil {
+		return -1
+	}
+	damage := u.hitDamage(w, target, u.UpgradeLevels())
+	levels := target.UpgradeLevels()
+
+	v := vitals{target.Health, target.Shield}
+	for hits := 1; ; hits++ {
+		v.attack(damage, w, target, levels)
+		if v.health <= 0 {
+			return hits
+		}
+	}
+}
+
+// weaponAgainst returns the weapon that does the most damage per second to the target.
+func (u Unit) weaponAgainst(target Unit) *api.Weapon {
+	if u.Unit == nil || target.Unit == nil {
+		return nil
+	}
+	targetType := api.Weapon_Ground
+	if target.IsFlying {
+		targetType = api.Weapon_Air
+	}
+
+	var best *api.Weapon
+	bestDPS := float32(0)
+	levels := u.UpgradeLevels()
+	for _, w := range u.Weapons {
+		if w == nil || w.Speed <= 0 || (w.Type != targetType && w.Type != api.Weapon_Any) {
+			continue
+		}
+		if dps := u.hitDamage(w, target, levels) * float32(hitsPerAttack(w)) / w.Speed; dps > bestDPS {
+			best, bestDPS = w, dps
+		}
+	}
+	return best
+}
+
+// hitDamage returns the damage of a single hit against the target before armor.
+func (u Unit) hitDamage(w *api.Weapon, target Unit, levels UpgradeLevels) float32 {
+	up := u.weaponUpgrade(w)
+	damage := w.Damage + up.damage*float32(levels.Attack)
+	for _, bonus := range w.DamageBonus {
+		if target.HasAttribute(bonus.Attribute) {
+			damage += bonus.Bonus + up.bonus*float32(levels.Attack)
+		}
+	}
+	return damage
+}
+
+// weaponUpgrade is the damage added by each weapon upgrade level, to the base damage and to
+// every attribute bonus.
+type weaponUpgrade struct {
+	damage, bonus float32
+}
+
+// weaponUpgradeKey identifies a weapon by the unit type and the targets it can attack.
+type weaponUpgradeKey struct {
+	unitType api.UnitTypeID
+	target   api.Weapon_TargetType
+}
+
+// weaponUpgrades lists the weapons that don't get the usual +1 per upgrade level since the game
+// data doesn't include it.
+var weaponUpgrades = map[weaponUpgradeKey]weaponUpgrade{
+	{terran.SCV, api.Weapon_Ground}:             {},
+	{terran.Marauder, api.Weapon_Ground}:        {1, 1},
+	{terran.Ghost, api.Weapon_Any}:              {1, 1},
+	{terran.Hellion, api.Weapon_Ground}:         {1, 1},
+	{terran.HellionTank, api.Weapon_Ground}:     {2, 1},
+	{terran.SiegeTank, api.Weapon_Ground}:       {2, 1},
+	{terran.SiegeTankSieged, api.Weapon_Ground}: {4, 1},
+	{terran.Thor, api.Weapon_Ground}:            {3, 0},
+	{terran.Thor, api.Weapon_Air}:               {1, 1},
+	{terran.VikingFighter, api.Weapon_Air}:      {1, 1},
+	{terran.LiberatorAG, api.Weapon_Ground}:     {5, 0},
+	{protoss.Probe, api.Weapon_Ground}:          {},
+	{protoss.Stalker, api.Weapon_Any}:           {1, 1},
+	{protoss.Adept, api.Weapon_Ground}:          {1, 1},
+	{protoss.DarkTemplar, api.Weapon_Ground}:    {5, 0},
+	{protoss.Archon, api.Weapon_Any}:            {3, 1},
+	{protoss.Immortal, api.Weapon_Ground}:       {2, 3},
+	{protoss.Colossus, api.Weapon_Ground}:       {1, 1},
+	{protoss.Tempest, api.Weapon_Ground}:        {4, 0},
+	{zerg.Drone, api.Weapon_Ground}:             {},
+	{zerg.Baneling, api.Weapon_Ground}:          {2, 2},
+	{zerg.Roach, api.Weapon_Ground}:             {2, 0},
+	{zerg.Ravager, api.Weapon_Ground}:           {2, 0},
+	{zerg.LurkerMPBurrowed, api.Weapon_Ground}:  {2, 1},
+	{zerg.Ultralisk, api.Weapon_Ground}:         {3, 0},
+	{zerg.Corruptor, api.Weapon_Air}:            {1, 1},
+	{zerg.BroodLord, api.Weapon_Ground}:         {2, 0},
+}
+
+// weaponUpgrade returns the damage each upgrade level adds to the unit's weapon.
+func (u Unit) weaponUpgrade(w *api.Weapon) weaponUpgrade {
+	if up, ok := weaponUpgrades[weaponUpgradeKey{u.UnitType, w.Type}]; ok {
+		return up
+	}
+	return weaponUpgrade{damage: 1}
+}
+
+func hitsPerAttack(w *api.Weapon) uint32 {
+	if w.Attacks == 0 {
+		return 1
+	}
+	return w.Attacks
+}
+
+// cooldownSeconds converts the weapon's cooldown into game seconds at "faster" speed.
+func cooldownSeconds(w *api.Weapon) float32 {
+	return w.Speed * normalLoopsPerSecond / LoopsPerSecond
+}
+
+// minDamage is the least a hit does regardless of armor.
+const minDamage = 0.5
+
+// vitals tracks the remaining health and shields of a unit taking damage.
+type vitals struct {
+	health, shields float32
+}
+
+// attack applies every hit of one attack and returns the total damage done.
+func (v *vitals) attack(damage float32, w *api.Weapon, target Unit, levels UpgradeLevels) float32 {
+	before := v.health + v.shields
+	for i := uint32(0); i < hitsPerAttack(w) && v.health > 0; i++ {
+		v.hit(damage, target.Armor+float32(levels.Armor), float32(levels.Shield))
+	}
+	if v.health < 0 {
+		v.health = 0
+	}
+	return before - v.health - v.shields
+}
+
+// hit applies a single hit, using up shields before health. Shield upgrades reduce damage to
+// shields and armor reduces damage to health, but every hit does at least half a point.
+func (v *vitals) hit(damage, armor, shieldArmor float32) {
+	if v.shields > 0 {
+		damage = float32(math.Max(float64(damage-shieldArmor), minDamage))
+		if damage <= v.shields {
+			v.shields -= damage
+			return
+		}
+		damage -= v.shields
+		v.shields = 0
+		v.health -= float32(math.Max(float64(damage-armor), 0))
+		return
+	}
+	v.health -= float32(math.Max(float64(damage-armor), minDamage))
+}
@@ -0,0 +1,87 @@
+package botutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/protoss"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+	"github.com/chippydip/go-sc2ai/enums/upgrade"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+func TestDamageAgainst(t *testing.T) {
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 1)
+	c.add(api.Alliance_Self, terran.Marauder, 1, 1)
+	c.add(api.Alliance_Enemy, zerg.Zergling, 2, 1)
+	c.add(api.Alliance_Enemy, zerg.Mutalisk, 3, 1)
+	c.add(api.Alliance_Enemy, protoss.Stalker, 4, 1)
+
+	get := func(ctx *botutil.UnitContext, unitType api.UnitTypeID) botutil.Unit {
+		return ctx.AllUnits().Choose(func(u botutil.Unit) bool { return u.UnitType == unitType }).ClosestTo(api.Point2D{})
+	}
+	expect := func(ctx *botutil.UnitContext, attacker, target api.UnitTypeID, damage float32, hits int) {
+		t.Helper()
+		a, b := get(ctx, attacker), get(ctx, target)
+		if d := a.DamageAgainst(b); d != damage {
+			t.Errorf("%v against %v: got damage %v, expected %v", attacker, target, d, damage)
+		}
+		if h := a.HitsToKill(b); h != hits {
+			t.Errorf("%v against %v: got %v hits, expected %v", attacker, target, h, hits)
+		}
+	}
+
+	ctx := c.context()
+	expect(ctx, terran.Marine, zerg.Zergling, 6, 6)
+	expect(ctx, terran.Marauder, protoss.Stalker, 20, 9) // 4 hits on shields, 5 more through 1 armor
+	expect(ctx, zerg.Zergling, zerg.Mutalisk, 0, -1)
+
+	if dps := get(ctx, terran.Marine).DPSAgainst(get(ctx, zerg.Zergling)); math.Abs(float64(dps-9.76)) > 0.01 {
+		t.Errorf("got %v dps, expected 9.76", dps)
+	}
+	if marine, ling := get(ctx, terran.Marine), get(ctx, zerg.Zergling); marine.AirDPS() != marine.GroundDPS() || ling.AirDPS() != 0 {
+		t.Errorf("got marine %v/%v and zergling %v air/ground dps", marine.AirDPS(), marine.GroundDPS(), ling.AirDPS())
+	}
+
+	// Our upgrades come from HasUpgrade, the enemy's from the estimate
+	c.upgrades = []api.UpgradeID{upgrade.TerranInfantryWeaponsLevel1}
+	ctx = c.context()
+	expect(ctx, terran.Marine, zerg.Zergling, 7, 5)
+
+	ctx.EnemyUpgrades = func(u botutil.Unit) botutil.UpgradeLevels { return botutil.UpgradeLevels{Armor: 2} }
+	expect(ctx, terran.Marine, zerg.Zergling, 5, 7)
+}
+
+func TestUpgradedDamage(t *testing.T) {
+	c := &combatUnits{}
+	c.add(api.Alliance_Enemy, protoss.Stalker, 0, 1)
+	c.add(api.Alliance_Enemy, zerg.Hydralisk, 1, 1)
+	c.add(api.Alliance_Enemy, zerg.Ultralisk, 2, 1)
+	c.add(api.Alliance_Self, terran.Marine, 3, 1)
+	c.add(api.Alliance_Self, terran.Marauder, 4, 1)
+
+	ctx := c.context()
+	ctx.EnemyUpgrades = func(u botutil.Unit) botutil.UpgradeLevels { return botutil.UpgradeLevels{Attack: 1} }
+	get := func(unitType api.UnitTypeID) botutil.Unit {
+		return ctx.AllUnits().Choose(func(u botutil.Unit) bool { return u.UnitType == unitType }).ClosestTo(api.Point2D{})
+	}
+	expect := func(attacker, target api.UnitTypeID, damage float32) {
+		t.Helper()
+		if d := get(attacker).DamageAgainst(get(target)); d != damage {
+			t.Errorf("%v against %v: got damage %v, expected %v", attacker, target, d, damage)
+		}
+	}
+	expect(protoss.Stalker, terran.Marine, 14)   // 13 +1
+	expect(protoss.Stalker, terran.Marauder, 19) // 13+5 armored +1 (+1 armored) -1 armor
+	expect(zerg.Hydralisk, terran.Marine, 13)    // 12 +1
+	expect(zerg.Ultralisk, terran.Marine, 38)    // 35 +3
+
+	// DPS isn't limited by the target's remaining health
+	get(terran.Marine).Health = 1
+	if dps := get(zerg.Ultralisk).DPSAgainst(get(terran.Marine)); math.Abs(float64(dps-38/(0.861/1.4))) > 0.01 {
+		t.Errorf("got %v dps, expected %v", dps, 38/(0.861/1.4))
+	}
+}
@@ -0,0 +1,231 @@
+package botutil
+
+import (
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+// Events compares each observation to the previous one and calls the registered callbacks for
+// changes to units. Callbacks are called after the UnitContext has been updated. Units that are
+// present in the first observation don't generate any events.
+type Events struct {
+	units    *UnitContext
+	prev     map[api.UnitTag]Unit
+	seen     map[api.UnitTag]bool // every unit of our own seen this game
+	dead     []api.UnitTag
+	upgrades func() []api.UpgradeID
+
+	created               []func(u Unit)
+	destroyed             []func(tag api.UnitTag, last Unit)
+	constructionStarted   []func(u Unit)
+	constructionCompleted []func(u Unit)
+	idle                  []func(u Unit)
+	damaged               []func(u Unit, amount float32)
+	enemyEnteredVision    []func(u Unit)
+	upgradeCompleted      []func(upgrade api.UpgradeID)
+	typeChanged           []func(u Unit, previous api.UnitTypeID)
+}
+
+// NewEvents creates a new event tracker and registers it to update after each step. It must be
+// created after the UnitContext so it sees the updated units.
+func NewEvents(info client.AgentInfo, units *UnitContext) *Events {
+	e := &Events{units: units, seen: map[api.UnitTag]bool{}, upgrades: info.Upgrades}
+
+	// Collect deaths from every observation since realtime steps may contain several
+	info.OnObservation(func() {
+		e.dead = append(e.dead, info.Observation().GetObservation().GetRawData().GetEvent().GetDeadUnits()...)
+	})
+
+	e.update()
+	info.OnAfterStep(e.update)
+	return e
+}
+
+// OnUnitCreated registers a callback for new units (but not structures) of our own, including
+// units hatching from an egg or cocoon. It is called once per unit, units that reappear after
+// being inside a transport or gas building are not new.
+func (e *Events) OnUnitCreated(callback func(u Unit)) {
+	if callback != nil {
+		e.created = append(e.created, callback)
+	}
+}
+
+// OnUnitDestroyed registers a callback for units of any alliance that died. The last known
+// state of the unit is passed if it was seen before, otherwise last.IsNil() is true.
+func (e *Events) OnUnitDestroyed(callback func(tag api.UnitTag, last Unit)) {
+	if callback != nil {
+		e.destroyed = append(e.destroyed, callback)
+	}
+}
+
+// OnConstructionStarted registers a callback for new structures of our own that aren't built yet,
+// including drones morphing into a structure.
+func (e *Events) OnConstructionStarted(callback func(u Unit)) {
+	if callback != nil {
+		e.constructionStarted = append(e.constructionStarted, callback)
+	}
+}
+
+// OnConstructionCompleted registers a callback for structures of our own that finished building.
+func (e *Events) OnConstructionCompleted(callback func(u Unit)) {
+	if callback != nil {
+		e.constructionCompleted = append(e.constructionCompleted, callback)
+	}
+}
+
+// OnUnitIdle registers a callback for units and structures of our own that had orders in the
+// previous observation but have none now.
+func (e *Events) OnUnitIdle(callback func(u Unit)) {
+	if callback != nil {
+		e.idle = append(e.idle, callback)
+	}
+}
+
+// OnUnitDamaged registers a callback for units of our own that lost health or shields (without
+// changing type).
+func (e *Events) OnUnitDamaged(callback func(u Unit, amount float32)) {
+	if callback != nil {
+		e.damaged = append(e.damaged, callback)
+	}
+}
+
+// OnEnemyEnteredVision registers a callback for enemy units that are visible now but weren't
+// visible (or weren't known at all) in the previous observation.
+func (e *Events) OnEnemyEnteredVision(callback func(u Unit)) {
+	if callback != nil {
+		e.enemyEnteredVision = append(e.enemyEnteredVision, callback)
+	}
+}
+
+// OnUpgradeCompleted registers a callback for each newly completed upgrade.
+func (e *Events) OnUpgradeCompleted(callback func(upgrade api.UpgradeID)) {
+	if callback != nil {
+		e.upgradeCompleted = append(e.upgradeCompleted, callback)
+	}
+}
+
+// OnUnitTypeChanged registers a callback for units of our own that morphed into another type.
+func (e *Events) OnUnitTypeChanged(callback func(u Unit, previous api.UnitTypeID)) {
+	if callback != nil {
+		e.typeChanged = append(e.typeChanged, callback)
+	}
+}
+
+func (e *Events) update() {
+	units := e.units.wrapped
+	if len(e.units.raw) == 0 {
+		units = nil // wrapped isn't reset when there are no units
+	}
+
+	first := e.prev == nil
+	prev := e.prev
+	e.prev = make(map[api.UnitTag]Unit, len(units))
+	for _, u := range units {
+		e.prev[u.Tag] = u
+	}
+
+	dead := e.dead
+	e.dead = nil
+	if first {
+		for _, u := range units {
+			if u.Alliance == api.Alliance_Self {
+				e.seen[u.Tag] = true
+			}
+		}
+		return
+	}
+
+	for _, tag := range dead {
+		last := prev[tag]
+		for _, cb := range e.destroyed {
+			cb(tag, last)
+		}
+	}
+
+	for _, u := range units {
+		p, seen := prev[u.Tag]
+
+		if u.Alliance == api.Alliance_Enemy {
+			if u.IsVisible() && !p.IsVisible() {
+				for _, cb := range e.enemyEnteredVision {
+					cb(u)
+				}
+			}
+			continue
+		}
+		if u.Alliance != api.Alliance_Self {
+			continue
+		}
+
+		if !seen {
+			if !e.seen[u.Tag] {
+				e.seen[u.Tag] = true
+				e.fireNew(u)
+			}
+			continue // otherwise it was loaded or inside a gas building
+		}
+
+		if u.UnitType != p.UnitType {
+			for _, cb := range e.typeChanged {
+				cb(u, p.UnitType)
+			}
+			if hatched(u, p.UnitType) || startedMorph(u, p) {
+				e.fireNew(u)
+			}
+		}
+		if u.IsStructure() && u.BuildProgress == 1 && p.BuildProgress < 1 {
+			for _, cb := range e.constructionCompleted {
+				cb(u)
+			}
+		}
+		if len(u.Orders) == 0 && len(p.Orders) > 0 {
+			for _, cb := range e.idle {
+				cb(u)
+			}
+		}
+		if amount := (p.Health + p.Shield) - (u.Health + u.Shield); amount > 0 && u.UnitType == p.UnitType {
+			for _, cb := range e.damaged {
+				cb(u, amount)
+			}
+		}
+	}
+
+	for _, upgrade := range e.upgrades() {
+		for _, cb := range e.upgradeCompleted {
+			cb(upgrade)
+		}
+	}
+}
+
+// hatched returns true if the unit just came out of an egg or cocoon.
+func hatched(u Unit, previous api.UnitTypeID) bool {
+	return isEgg(previous) && !isEgg(u.UnitType)
+}
+
+// startedMorph returns true if a unit (such as a drone) just morphed into an unfinished structure.
+func startedMorph(u, previous Unit) bool {
+	return u.IsStructure() && u.BuildProgress < 1 && !previous.IsStructure()
+}
+
+func isEgg(unitType api.UnitTypeID) bool {
+	switch unitType {
+	case zerg.Egg, zerg.BanelingCocoon, zerg.BroodLordCocoon, zerg.LurkerMPEgg, zerg.OverlordCocoon,
+		zerg.RavagerCocoon, zerg.TransportOverlordCocoon:
+		return true
+	}
+	return false
+}
+
+// fireNew calls the callbacks for a new unit of our own.
+func (e *Events) fireNew(u Unit) {
+	if !u.IsStructure() {
+		for _, cb := range e.created {
+			cb(u)
+		}
+	} else if u.BuildProgress < 1 {
+		for _, cb := range e.constructionStarted {
+			cb(u)
+		}
+	}
+}
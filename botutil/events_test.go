@@ -0,0 +1,94 @@
+package botutil_test
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+func TestEvents(t *testing.T) {
+	units := append([]*api.UnitTypeData(nil), unitData...)
+	units[zerg.Egg] = &api.UnitTypeData{}
+	i := &fakeInfo{data: &api.ResponseData{Units: units}}
+	drone := &api.Unit{Tag: 1, UnitType: zerg.Drone, Alliance: api.Alliance_Self, Health: 40, Orders: []*api.UnitOrder{{}}}
+	i.step(nil, drone)
+
+	ctx := botutil.NewUnitContext(i, nil)
+	e := botutil.NewEvents(i, ctx)
+
+	var log []string
+	e.OnUnitCreated(func(u botutil.Unit) { log = append(log, "created") })
+	e.OnUnitDestroyed(func(tag api.UnitTag, last botutil.Unit) {
+		if last.IsNil() {
+			log = append(log, "destroyed unknown")
+		} else {
+			log = append(log, "destroyed")
+		}
+	})
+	e.OnConstructionStarted(func(u botutil.Unit) { log = append(log, "started") })
+	e.OnConstructionCompleted(func(u botutil.Unit) { log = append(log, "completed") })
+	e.OnUnitIdle(func(u botutil.Unit) { log = append(log, "idle") })
+	e.OnUnitDamaged(func(u botutil.Unit, amount float32) {
+		if amount != 15 {
+			t.Errorf("expected 15 damage, got %v", amount)
+		}
+		log = append(log, "damaged")
+	})
+	e.OnEnemyEnteredVision(func(u botutil.Unit) { log = append(log, "enemy") })
+	e.OnUpgradeCompleted(func(upgrade api.UpgradeID) { log = append(log, "upgrade") })
+	e.OnUnitTypeChanged(func(u botutil.Unit, previous api.UnitTypeID) {
+		if previous != zerg.Drone && previous != zerg.Egg {
+			t.Errorf("expected previous type Drone or Egg, got %v", previous)
+		}
+		log = append(log, "morphed")
+	})
+
+	expect := func(events ...string) {
+		t.Helper()
+		if len(log) != len(events) {
+			t.Fatalf("got events %v, expected %v", log, events)
+		}
+		for j := range events {
+			if log[j] != events[j] {
+				t.Fatalf("got events %v, expected %v", log, events)
+			}
+		}
+		log = nil
+	}
+
+	// New units, idle and damage
+	i.step(nil,
+		&api.Unit{Tag: 1, UnitType: zerg.Drone, Alliance: api.Alliance_Self, Health: 25},
+		&api.Unit{Tag: 2, UnitType: zerg.Zergling, Alliance: api.Alliance_Self, Health: 35},
+		&api.Unit{Tag: 3, UnitType: zerg.Zergling, Alliance: api.Alliance_Enemy, DisplayType: api.DisplayType_Snapshot})
+	expect("idle", "damaged", "created")
+
+	// Construction, vision and upgrades
+	i.upgrades = []api.UpgradeID{1}
+	i.step(nil,
+		&api.Unit{Tag: 1, UnitType: zerg.Hatchery, Alliance: api.Alliance_Self, Health: 25, BuildProgress: 0.1},
+		&api.Unit{Tag: 2, UnitType: zerg.Zergling, Alliance: api.Alliance_Self, Health: 35},
+		&api.Unit{Tag: 3, UnitType: zerg.Zergling, Alliance: api.Alliance_Enemy, DisplayType: api.DisplayType_Visible},
+		&api.Unit{Tag: 4, UnitType: zerg.Hatchery, Alliance: api.Alliance_Self, Health: 100, BuildProgress: 0.5})
+	expect("morphed", "started", "started", "enemy", "upgrade")
+
+	// Completion and deaths
+	i.upgrades = nil
+	i.step([]api.UnitTag{2, 3, 5},
+		&api.Unit{Tag: 1, UnitType: zerg.Hatchery, Alliance: api.Alliance_Self, Health: 25, BuildProgress: 0.2},
+		&api.Unit{Tag: 4, UnitType: zerg.Hatchery, Alliance: api.Alliance_Self, Health: 100, BuildProgress: 1})
+	expect("destroyed", "destroyed", "destroyed unknown", "completed")
+
+	// Units hatching from eggs are created, units coming back out of a transport are not
+	hatchery := &api.Unit{Tag: 4, UnitType: zerg.Hatchery, Alliance: api.Alliance_Self, Health: 100, BuildProgress: 1}
+	i.step(nil, hatchery, &api.Unit{Tag: 6, UnitType: zerg.Egg, Alliance: api.Alliance_Self, Health: 200})
+	expect("created")
+	zergling := &api.Unit{Tag: 6, UnitType: zerg.Zergling, Alliance: api.Alliance_Self, Health: 35}
+	i.step(nil, hatchery, zergling)
+	expect("morphed", "created")
+	i.step(nil, hatchery)
+	i.step(nil, hatchery, zergling)
+	expect()
+}
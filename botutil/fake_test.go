@@ -0,0 +1,86 @@
+package botutil_test
+
+import (
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// fakeInfo is an AgentInfo that replays observations built by the tests. Callbacks registered
+// with it are run by step.
+type fakeInfo struct {
+	mockAgentInfo
+	data        *api.ResponseData
+	gameInfo    *api.ResponseGameInfo
+	obs         *api.ResponseObservation
+	upgrades    []api.UpgradeID
+	visibility  *api.ImageData
+	abilities   map[api.UnitTag][]api.AbilityID
+	observation []func()
+	afterStep   []func()
+
+	// Included in the next observation
+	gameLoop     uint32
+	player       *api.PlayerCommon
+	actionErrors []*api.ActionError
+}
+
+func (i *fakeInfo) Data() *api.ResponseData {
+	if i.data != nil {
+		return i.data
+	}
+	return data
+}
+func (i *fakeInfo) GameInfo() *api.ResponseGameInfo       { return i.gameInfo }
+func (i *fakeInfo) Observation() *api.ResponseObservation { return i.obs }
+func (i *fakeInfo) Upgrades() []api.UpgradeID             { return i.upgrades }
+func (i *fakeInfo) OnObservation(f func())                { i.observation = append(i.observation, f) }
+func (i *fakeInfo) OnAfterStep(f func())                  { i.afterStep = append(i.afterStep, f) }
+
+func (i *fakeInfo) HasUpgrade(upgrade api.UpgradeID) bool {
+	for _, u := range i.upgrades {
+		if u == upgrade {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *fakeInfo) SendActions(actions []*api.Action) []api.ActionResult {
+	results := make([]api.ActionResult, len(actions))
+	for j := range results {
+		results[j] = api.ActionResult_Success
+	}
+	return results
+}
+
+func (i *fakeInfo) Query(query api.RequestQuery) *api.ResponseQuery {
+	r := &api.ResponseQuery{}
+	for _, q := range query.Abilities {
+		available := &api.ResponseQueryAvailableAbilities{UnitTag: q.UnitTag}
+		for _, a := range i.abilities[q.UnitTag] {
+			available.Abilities = append(available.Abilities, &api.AvailableAbility{AbilityId: a})
+		}
+		r.Abilities = append(r.Abilities, available)
+	}
+	return r
+}
+
+func (i *fakeInfo) step(dead []api.UnitTag, units ...*api.Unit) {
+	i.obs = &api.ResponseObservation{
+		ActionErrors: i.actionErrors,
+		Observation: &api.Observation{
+			GameLoop:     i.gameLoop,
+			PlayerCommon: i.player,
+			RawData: &api.ObservationRaw{
+				Units:    units,
+				Event:    &api.Event{DeadUnits: dead},
+				MapState: &api.MapState{Visibility: i.visibility},
+			},
+		},
+	}
+	for _, f := range i.observation {
+		f()
+	}
+	for _, f := range i.afterStep {
+		f()
+	}
+}
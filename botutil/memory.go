@@ -0,0 +1,113 @@
+package botutil
+
+import (
+	"sort"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+)
+
+// EnemyMemory remembers the last known state of enemy units after they leave vision. Entries are
+// forgotten when the unit dies or when its remembered position is visible but the unit isn't there.
+type EnemyMemory struct {
+	info    client.AgentInfo
+	units   *UnitContext
+	enemies map[api.UnitTag]*rememberedUnit
+}
+
+type rememberedUnit struct {
+	unit     Unit
+	lastSeen uint32
+}
+
+// NewEnemyMemory creates a new enemy memory and registers it to update after each step. It must
+// be created after the UnitContext and Events so it sees the current units and deaths.
+func NewEnemyMemory(info client.AgentInfo, units *UnitContext, events *Events) *EnemyMemory {
+	m := &EnemyMemory{
+		info:    info,
+		units:   units,
+		enemies: map[api.UnitTag]*rememberedUnit{},
+	}
+	events.OnUnitDestroyed(func(tag api.UnitTag, last Unit) {
+		delete(m.enemies, tag)
+	})
+
+	m.update()
+	info.OnAfterStep(m.update)
+	return m
+}
+
+func (m *EnemyMemory) update() {
+	obs := m.info.Observation().GetObservation()
+	gameLoop := obs.GetGameLoop()
+
+	seen := map[api.UnitTag]bool{}
+	if len(m.units.raw) > 0 {
+		for _, u := range m.units.wrapped {
+			if u.Alliance != api.Alliance_Enemy {
+				continue
+			}
+			seen[u.Tag] = true
+
+			// Snapshots may be stale, so only use them for units we don't know about yet
+			if r, ok := m.enemies[u.Tag]; !ok {
+				m.enemies[u.Tag] = &rememberedUnit{u, gameLoop}
+			} else if u.IsVisible() {
+				r.unit, r.lastSeen = u, gameLoop
+			}
+		}
+	}
+
+	visibility := obs.GetRawData().GetMapState().GetVisibility()
+	if visibility == nil {
+		return
+	}
+	grid := visibility.Bytes()
+	for tag, r := range m.enemies {
+		pos := r.unit.Pos2D()
+		if !seen[tag] && grid.Get(int32(pos.X), int32(pos.Y)) == api.VisibilityVisible {
+			delete(m.enemies, tag)
+		}
+	}
+}
+
+// RememberedEnemies returns the last known state of every remembered enemy unit, including the
+// ones that are currently visible.
+func (m *EnemyMemory) RememberedEnemies() Units {
+	raw := make([]Unit, 0, len(m.enemies))
+	for _, r := range m.enemies {
+		raw = append(raw, r.unit)
+	}
+	sort.Slice(raw, func(i, j int) bool { return raw[i].Tag < raw[j].Tag })
+	return NewUnits(raw)
+}
+
+// RememberedEnemiesNear returns the remembered enemy units last seen within dist of pos.
+func (m *EnemyMemory) RememberedEnemiesNear(pos api.Point2D, dist float32) Units {
+	return m.RememberedEnemies().CloserThan(dist, pos)
+}
+
+// LastSeen returns the game loop when the enemy unit was last visible.
+func (m *EnemyMemory) LastSeen(tag api.UnitTag) (uint32, bool) {
+	if r, ok := m.enemies[tag]; ok {
+		return r.lastSeen, true
+	}
+	return 0, false
+}
+
+// KnownEnemyArmySupply returns the supply used by remembered enemy units that aren't workers or
+// structures.
+func (m *EnemyMemory) KnownEnemyArmySupply() float32 {
+	supply := float32(0)
+	for _, r := range m.enemies {
+		if u := r.unit; u.UnitTypeData != nil && !u.IsStructure() && !u.IsWorker() {
+			supply += u.FoodRequired
+		}
+	}
+	return supply
+}
+
+// ForgetEnemy removes a unit from memory (e.g. if it's known to have left).
+func (m *EnemyMemory) ForgetEnemy(tag api.UnitTag) {
+	delete(m.enemies, tag)
+}
@@ -0,0 +1,53 @@
+package botutil_test
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+func TestEnemyMemory(t *testing.T) {
+	units := append([]*api.UnitTypeData(nil), unitData...)
+	zerglingData := *unitData[zerg.Zergling]
+	zerglingData.FoodRequired = 0.5
+	units[zerg.Zergling] = &zerglingData
+
+	i := &fakeInfo{data: &api.ResponseData{Units: units}, visibility: &api.ImageData{BitsPerPixel: 8, Size_: &api.Size2DI{X: 16, Y: 16}, Data: make([]byte, 16*16)}}
+	i.step(nil)
+
+	ctx := botutil.NewUnitContext(i, nil)
+	m := botutil.NewEnemyMemory(i, ctx, botutil.NewEvents(i, ctx))
+
+	zergling := func(tag api.UnitTag, x float32) *api.Unit {
+		return &api.Unit{Tag: tag, UnitType: zerg.Zergling, Alliance: api.Alliance_Enemy,
+			DisplayType: api.DisplayType_Visible, Pos: &api.Point{X: x, Y: 1}, Health: 35}
+	}
+	i.step(nil, zergling(1, 1), zergling(2, 5), zergling(3, 10), &api.Unit{Tag: 4, UnitType: zerg.Drone, Alliance: api.Alliance_Enemy, Pos: &api.Point{X: 12, Y: 12}})
+	if supply := m.KnownEnemyArmySupply(); supply != 1.5 {
+		t.Errorf("expected 1.5 army supply, got %v", supply)
+	}
+
+	// Out of vision they are still remembered, except where the spot is visible
+	i.visibility.Data[1*16+10] = api.VisibilityVisible
+	i.step([]api.UnitTag{4})
+	if n := m.RememberedEnemies().Len(); n != 2 {
+		t.Errorf("expected 2 remembered enemies, got %v", n)
+	}
+	if n := m.RememberedEnemiesNear(api.Point2D{X: 0, Y: 0}, 3).Len(); n != 1 {
+		t.Errorf("expected 1 remembered enemy near origin, got %v", n)
+	}
+	if loop, ok := m.LastSeen(2); !ok || loop != 0 {
+		t.Errorf("expected unit 2 to be remembered, got %v %v", loop, ok)
+	}
+	if supply := m.KnownEnemyArmySupply(); supply != 1 {
+		t.Errorf("expected 1 army supply, got %v", supply)
+	}
+
+	// Deaths are forgotten even when not visible
+	i.step([]api.UnitTag{1})
+	if n := m.RememberedEnemies().Len(); n != 1 {
+		t.Errorf("expected 1 remembered enemy, got %v", n)
+	}
+}
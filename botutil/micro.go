@@ -0,0 +1,106 @@
+package botutil
+
+import (
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// weaponReadyLoops is the weapon cooldown below which a unit should be attacking rather than
+// moving. Moving any earlier would cancel the next shot.
+const weaponReadyLoops = 1
+
+// WeaponReady returns true if the unit's weapon is ready (or about to be ready) to fire.
+func (u Unit) WeaponReady() bool {
+	return u.Unit != nil && u.WeaponCooldown <= weaponReadyLoops
+}
+
+// HealthFraction returns the unit's combined health and shields as a fraction of the maximum.
+func (u Unit) HealthFraction() float32 {
+	if u.Unit == nil || u.HealthMax+u.ShieldMax <= 0 {
+		return 0
+	}
+	return (u.Health + u.Shield) / (u.HealthMax + u.ShieldMax)
+}
+
+// Kite attacks the target whenever the weapon is ready and otherwise backs away while the target
+// could hit us. It returns true if the unit is moving away this step.
+func (u Unit) Kite(target Unit) bool {
+	if u.Unit == nil || target.IsNil() {
+		return false
+	}
+	pos, from := u.Pos2D(), target.Pos2D()
+	if !u.WeaponReady() && target.IsInWeaponsRange(u, kiteMargin(target)) && pos.Distance2(from) > 0 {
+		// Back off to just outside of the target's range. The point only depends on where the
+		// target is, so the order isn't replaced every step while we are still moving to it.
+		dist := target.WeaponRange(u) + u.Radius + target.Radius + 2*kiteMargin(target)
+		u.MoveTo(from.Add(from.DirTo(pos).Mul(dist)), kiteMargin(target)+1)
+		return true
+	}
+	// Without a direction to back off in (e.g. stacked on top of the target) keep attacking
+	u.AttackTarget(target)
+	return false
+}
+
+// kiteMargin is how far outside of the target's range we want to be while our weapon recharges.
+func kiteMargin(target Unit) float32 {
+	return target.MovementSpeed / 2
+}
+
+// StutterStep attacks the target whenever the weapon is ready and otherwise moves towards it so
+// it can't escape. It returns true if the unit is moving this step.
+func (u Unit) StutterStep(target Unit) bool {
+	if u.Unit == nil || target.IsNil() {
+		return false
+	}
+	if !u.WeaponReady() && !u.IsInWeaponsRange(target, -1) {
+		u.MoveTo(target.Pos2D(), 1)
+		return true
+	}
+	u.AttackTarget(target)
+	return false
+}
+
+// RetreatWhenLow moves the unit to safety if its health and shields are at or below the given
+// fraction. It returns true if the unit is retreating.
+func (u Unit) RetreatWhenLow(safety api.Point2D, fraction float32) bool {
+	if u.Unit == nil || u.HealthFraction() > fraction {
+		return false
+	}
+	u.MoveTo(safety, 1)
+	return true
+}
+
+// FocusTarget picks the target to focus fire from the given candidates. Targets already in range
+// are preferred, then those that take the fewest hits to kill and then those that do the most
+// damage to us. If nothing is in range the closest target we can attack is returned.
+func (u Unit) FocusTarget(targets Units) Unit {
+	if u.Unit == nil {
+		return Unit{}
+	}
+
+	var best, closest Unit
+	bestHits, bestDPS := 0, float32(0)
+	closestDist := float32(0)
+	targets.Each(func(t Unit) {
+		if !t.CanBeTargeted() {
+			return
+		}
+		hits := u.HitsToKill(t)
+		if hits < 0 {
+			return // can't hurt it
+		}
+		if !u.IsInWeaponsRange(t, 0) {
+			if d := u.Pos2D().Distance2(t.Pos2D()); closest.IsNil() || d < closestDist {
+				closest, closestDist = t, d
+			}
+			return
+		}
+		dps := t.DPSAgainst(u)
+		if best.IsNil() || hits < bestHits || (hits == bestHits && dps > bestDPS) {
+			best, bestHits, bestDPS = t, hits, dps
+		}
+	})
+	if best.IsNil() {
+		return closest
+	}
+	return best
+}
@@ -0,0 +1,210 @@
+package botutil_test
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+func TestFocusTarget(t *testing.T) {
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 1)
+	c.add(api.Alliance_Enemy, zerg.Zergling, 3, 2)
+	c.add(api.Alliance_Enemy, zerg.Zergling, 30, 1)
+	marine, healthy, weak, far := c.units[0], c.units[1], c.units[2], c.units[3]
+	healthy.Health, weak.Health, far.Health = 30, 10, 1
+
+	ctx := c.context()
+	if target := ctx.UnitByTag(marine.Tag).FocusTarget(ctx.Enemy.All()); target.Tag != weak.Tag {
+		t.Errorf("expected the weakest zergling in range, got %v", target.Tag)
+	}
+
+	healthy.Pos.X, weak.Pos.X = 20, 25
+	ctx = c.context()
+	if target := ctx.UnitByTag(marine.Tag).FocusTarget(ctx.Enemy.All()); target.Tag != healthy.Tag {
+		t.Errorf("expected the closest zergling when none are in range, got %v", target.Tag)
+	}
+}
+
+// microTest runs a single marine against a single zergling and checks the orders it is given.
+type microTest struct {
+	t        *testing.T
+	info     *fakeInfo
+	bot      *botutil.Bot
+	marine   *api.Unit
+	zergling *api.Unit
+}
+
+func newMicroTest(t *testing.T) *microTest {
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 1)
+	c.add(api.Alliance_Enemy, zerg.Zergling, 1.5, 1)
+	c.units[0].HealthMax = 45
+
+	m := &microTest{t: t, marine: c.units[0], zergling: c.units[1]}
+	m.info = &fakeInfo{
+		data:      combatData,
+		gameInfo:  &api.ResponseGameInfo{StartRaw: &api.StartRaw{StartLocations: []*api.Point2D{{X: 100, Y: 100}}}},
+		abilities: map[api.UnitTag][]api.AbilityID{m.marine.Tag: {ability.Move, ability.Attack}},
+	}
+	m.info.step(nil, c.units...)
+	m.bot = botutil.NewBot(m.info)
+	return m
+}
+
+// step observes the current state of both units and returns the marine and its target.
+func (m *microTest) step() (botutil.Unit, botutil.Unit) {
+	m.info.gameLoop++
+	m.info.step(nil, m.marine, m.zergling)
+	return m.bot.UnitByTag(m.marine.Tag), m.bot.UnitByTag(m.zergling.Tag)
+}
+
+// sent sends the marine's orders and returns the command, or nil if nothing was sent.
+func (m *microTest) sent() *api.ActionRawUnitCommand {
+	m.t.Helper()
+	m.bot.Actions.Send()
+	sent := m.bot.Actions.PrevActions()
+	if len(sent) == 0 || sent[0] == nil {
+		return nil
+	}
+	if len(sent) != 1 {
+		m.t.Fatalf("expected a single command, got %v", sent)
+	}
+	cmd := sent[0].GetActionRaw().GetUnitCommand()
+	sent[0] = nil // PrevActions isn't cleared when nothing is sent
+	return cmd
+}
+
+// moveOrder gives the marine the order it was just sent, as the game would.
+func (m *microTest) moveOrder(cmd *api.ActionRawUnitCommand) {
+	p := cmd.GetTargetWorldSpacePos()
+	m.marine.Orders = []*api.UnitOrder{{AbilityId: ability.Move,
+		Target: &api.UnitOrder_TargetWorldSpacePos{TargetWorldSpacePos: &api.Point{X: p.X, Y: p.Y}}}}
+}
+
+func TestWeaponReady(t *testing.T) {
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 1)
+	for _, test := range []struct {
+		cooldown float32
+		ready    bool
+	}{{0, true}, {1, true}, {1.5, false}, {10, false}} {
+		c.units[0].WeaponCooldown = test.cooldown
+		if ready := c.context().UnitByTag(c.units[0].Tag).WeaponReady(); ready != test.ready {
+			t.Errorf("cooldown %v: got %v, expected %v", test.cooldown, ready, test.ready)
+		}
+	}
+	if (botutil.Unit{}).WeaponReady() {
+		t.Error("expected a nil unit not to be ready")
+	}
+}
+
+func TestKite(t *testing.T) {
+	m := newMicroTest(t)
+
+	// Attack while the weapon is ready
+	if u, z := m.step(); u.Kite(z) {
+		t.Error("expected to attack with the weapon ready")
+	}
+	if cmd := m.sent(); cmd.GetAbilityId() != ability.Attack || cmd.GetTargetUnitTag() != m.zergling.Tag {
+		t.Errorf("expected to attack the zergling, got %v", cmd)
+	}
+	m.marine.Orders = []*api.UnitOrder{{AbilityId: ability.Attack,
+		Target: &api.UnitOrder_TargetUnitTag{TargetUnitTag: m.zergling.Tag}}}
+	if u, z := m.step(); u.Kite(z) || m.sent() != nil {
+		t.Error("expected the current attack order to be kept")
+	}
+
+	// Back off directly away from the target while on cooldown
+	m.marine.WeaponCooldown = 10
+	if u, z := m.step(); !u.Kite(z) {
+		t.Error("expected to back off while on cooldown")
+	}
+	cmd := m.sent()
+	if cmd.GetAbilityId() != ability.Move {
+		t.Fatalf("expected to move, got %v", cmd)
+	}
+	if p := cmd.GetTargetWorldSpacePos(); p.X > -2 || p.Y != 0 {
+		t.Errorf("expected to move out of range directly away from the zergling, got %v", p)
+	}
+
+	// Keep moving to the same point as both units move
+	m.moveOrder(cmd)
+	m.marine.Pos.X, m.zergling.Pos.X = -1.5, 0.2
+	if u, z := m.step(); !u.Kite(z) || m.sent() != nil {
+		t.Error("expected the current move order to be kept")
+	}
+
+	// Out of range of the target there's nothing to back off from
+	m.zergling.Pos.X = 10
+	if u, z := m.step(); u.Kite(z) {
+		t.Error("expected not to back off out of range")
+	}
+	if cmd := m.sent(); cmd.GetAbilityId() != ability.Attack {
+		t.Errorf("expected to attack, got %v", cmd)
+	}
+
+	// With no direction to back off in keep attacking
+	m.zergling.Pos.X = m.marine.Pos.X
+	if u, z := m.step(); u.Kite(z) {
+		t.Error("expected not to back off when on top of the target")
+	}
+	if cmd := m.sent(); cmd.GetAbilityId() != ability.Attack {
+		t.Errorf("expected to attack, got %v", cmd)
+	}
+}
+
+func TestStutterStep(t *testing.T) {
+	m := newMicroTest(t)
+	m.zergling.Pos.X = 10
+	m.marine.WeaponCooldown = 10
+
+	// Chase a target that is out of range while on cooldown
+	if u, z := m.step(); !u.StutterStep(z) {
+		t.Error("expected to chase the target")
+	}
+	cmd := m.sent()
+	if p := cmd.GetTargetWorldSpacePos(); cmd.GetAbilityId() != ability.Move || p.X != 10 || p.Y != 0 {
+		t.Fatalf("expected to move to the zergling, got %v", cmd)
+	}
+	m.moveOrder(cmd)
+	m.zergling.Pos.X = 10.5
+	if u, z := m.step(); !u.StutterStep(z) || m.sent() != nil {
+		t.Error("expected the current move order to be kept")
+	}
+
+	// Attack once the weapon is ready
+	m.marine.WeaponCooldown = 0
+	if u, z := m.step(); u.StutterStep(z) {
+		t.Error("expected to attack with the weapon ready")
+	}
+	if cmd := m.sent(); cmd.GetAbilityId() != ability.Attack {
+		t.Errorf("expected to attack, got %v", cmd)
+	}
+}
+
+func TestRetreatWhenLow(t *testing.T) {
+	m := newMicroTest(t)
+	safety := api.Point2D{X: -20, Y: 0}
+
+	if u, _ := m.step(); u.RetreatWhenLow(safety, 0.5) || m.sent() != nil {
+		t.Error("expected not to retreat at full health")
+	}
+
+	m.marine.Health = 0.5 * 45
+	if u, _ := m.step(); !u.RetreatWhenLow(safety, 0.5) {
+		t.Error("expected to retreat at half health")
+	}
+	cmd := m.sent()
+	if p := cmd.GetTargetWorldSpacePos(); cmd.GetAbilityId() != ability.Move || p.X != safety.X || p.Y != safety.Y {
+		t.Fatalf("expected to move to safety, got %v", cmd)
+	}
+	m.moveOrder(cmd)
+	if u, _ := m.step(); !u.RetreatWhenLow(safety, 0.5) || m.sent() != nil {
+		t.Error("expected the current move order to be kept")
+	}
+}
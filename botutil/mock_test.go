@@ -1,6 +1,11 @@
 package botutil_test
 
-import "github.com/chippydip/go-sc2ai/api"
+import (
+	"log/slog"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+)
 
 type mockAgentInfo struct{}
 
@@ -51,6 +56,17 @@ func (a *mockAgentInfo) ClearDebugDraw() {
 }
 func (a *mockAgentInfo) LeaveGame() {
 }
+func (a *mockAgentInfo) SaveReplay(path string) {
+}
+func (a *mockAgentInfo) QuickSave() error {
+	panic("Not Implemented")
+}
+func (a *mockAgentInfo) QuickLoad() error {
+	panic("Not Implemented")
+}
+func (a *mockAgentInfo) RestartGame() error {
+	panic("Not Implemented")
+}
 
 func (a *mockAgentInfo) OnBeforeStep(func()) {
 }
@@ -58,6 +74,31 @@ func (a *mockAgentInfo) OnObservation(func()) {
 }
 func (a *mockAgentInfo) OnAfterStep(func()) {
 }
+func (a *mockAgentInfo) OnDisconnect(func(error)) {
+}
+func (a *mockAgentInfo) OnReconnect(func()) {
+}
 
 func (a *mockAgentInfo) SetPerfInterval(steps uint32) {
 }
+func (a *mockAgentInfo) SetMetricsSink(sinks ...client.MetricsSink) {
+}
+func (a *mockAgentInfo) Logger() *slog.Logger {
+	return slog.Default()
+}
+
+func (a *mockAgentInfo) GetClientErrors() []client.ClientError {
+	return nil
+}
+func (a *mockAgentInfo) GetProtocolErrors() []string {
+	return nil
+}
+func (a *mockAgentInfo) ClearClientErrors() {
+}
+func (a *mockAgentInfo) ClearProtocolErrors() {
+}
+func (a *mockAgentInfo) ErrorIf(condition bool, err error) bool {
+	return condition
+}
+func (a *mockAgentInfo) OnError(func(client.ClientError)) {
+}
@@ -1,8 +1,6 @@
 package botutil
 
 import (
-	"log"
-
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/client"
 )
@@ -14,6 +12,9 @@ type Player struct {
 
 	OpponentID   api.PlayerID
 	OpponentRace api.Race
+
+	gameLoop     uint32
+	reservations []*Reservation
 }
 
 // NewPlayer ...
@@ -28,8 +29,14 @@ func NewPlayer(info client.AgentInfo) *Player {
 		}
 	}
 	update := func() {
+		p.gameLoop = info.Observation().GetObservation().GetGameLoop()
+		// Orders can also fail once a worker reaches its build site
+		for _, e := range info.Observation().GetActionErrors() {
+			p.releaseUnit(e.GetUnitTag())
+		}
 		if pc := info.Observation().GetObservation().GetPlayerCommon(); pc != nil {
 			p.PlayerCommon = *pc
+			p.updateReservations(p.gameLoop)
 		}
 
 		if p.OpponentRace == api.Race_Random {
@@ -37,7 +44,7 @@ func NewPlayer(info client.AgentInfo) *Player {
 				if u.GetOwner() == p.OpponentID {
 					data := info.Data().GetUnits()[u.GetUnitType()]
 					p.OpponentRace = data.GetRace()
-					log.Printf("Detected OpponentRace: %v", p.OpponentRace)
+					info.Logger().Info("Detected opponent race", "race", p.OpponentRace)
 					break
 				}
 			}
@@ -0,0 +1,166 @@
+package botutil
+
+import (
+	"fmt"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// Reservation holds resources for an order that won't be paid for until a later step, such as a
+// worker walking to a build site. Reserved resources are subtracted from the player's Minerals,
+// Vespene and FoodUsed after every observation until the reservation is released.
+type Reservation struct {
+	Owner   string         // unique key, reserving again with the same owner replaces the old reservation
+	Cost    Cost           // resources to hold
+	Expires uint32         // game loop at which the reservation is dropped (0 to never expire)
+	Target  api.UnitTypeID // released when one of our units of this type appears (optional)
+	Unit    api.UnitTag    // released when an order for this unit fails (optional)
+
+	held Cost // amount actually subtracted this step (may be less than Cost)
+}
+
+// buildReservationLoops is how long a worker has to reach its build site (about 30 seconds).
+const buildReservationLoops = 672
+
+// Reserve holds resources across steps until the reservation is released, expires, or its
+// target appears. The cost is subtracted immediately so CanAfford sees it.
+func (p *Player) Reserve(r Reservation) {
+	p.Release(r.Owner)
+	p.hold(&r)
+	p.reservations = append(p.reservations, &r)
+}
+
+// Release returns the resources held by owner's reservation (if any) to the available pool.
+func (p *Player) Release(owner string) {
+	for i, r := range p.reservations {
+		if r.Owner == owner {
+			p.release(i)
+			return
+		}
+	}
+}
+
+// Reserved returns the total cost of all active reservations.
+func (p *Player) Reserved() Cost {
+	total := Cost{}
+	for _, r := range p.reservations {
+		total.Minerals += r.Cost.Minerals
+		total.Vespene += r.Cost.Vespene
+		total.Food += r.Cost.Food
+	}
+	return total
+}
+
+// Reservations returns a copy of the active reservations.
+func (p *Player) Reservations() []Reservation {
+	rs := make([]Reservation, len(p.reservations))
+	for i, r := range p.reservations {
+		rs[i] = *r
+	}
+	return rs
+}
+
+// hold subtracts as much of the reservation as is currently available.
+func (p *Player) hold(r *Reservation) {
+	r.held = Cost{
+		Minerals: minUint32(r.Cost.Minerals, p.Minerals),
+		Vespene:  minUint32(r.Cost.Vespene, p.Vespene),
+		Food:     r.Cost.Food,
+	}
+	p.Spend(r.held)
+}
+
+func (p *Player) release(i int) {
+	r := p.reservations[i]
+	p.Minerals += r.held.Minerals
+	p.Vespene += r.held.Vespene
+	p.FoodUsed -= r.held.Food
+	p.reservations = append(p.reservations[:i], p.reservations[i+1:]...)
+}
+
+// updateReservations drops expired reservations and re-applies the rest to a new observation.
+func (p *Player) updateReservations(gameLoop uint32) {
+	rs := p.reservations[:0]
+	for _, r := range p.reservations {
+		if r.Expires == 0 || gameLoop < r.Expires {
+			p.hold(r)
+			rs = append(rs, r)
+		}
+	}
+	p.reservations = rs
+}
+
+// releaseTarget releases the first reservation waiting for a unit of the given type.
+func (p *Player) releaseTarget(unitType api.UnitTypeID) {
+	for i, r := range p.reservations {
+		if r.Target != 0 && r.Target == unitType {
+			p.release(i)
+			return
+		}
+	}
+}
+
+// releaseUnit releases any reservations for orders given to the unit.
+func (p *Player) releaseUnit(tag api.UnitTag) {
+	for i := len(p.reservations) - 1; i >= 0; i-- {
+		if p.reservations[i].Unit == tag {
+			p.release(i)
+		}
+	}
+}
+
+// watchReservations registers the hooks that release reservations when their target appears
+// or their order fails.
+func (p *Player) watchReservations(events *Events, actions *Actions) {
+	released := func(u Unit) { p.releaseTarget(u.UnitType) }
+	events.OnUnitCreated(released)
+	events.OnConstructionStarted(released)
+	events.OnUnitTypeChanged(func(u Unit, previous api.UnitTypeID) {
+		if !hatched(u, previous) && !(u.IsStructure() && u.BuildProgress < 1) {
+			released(u) // otherwise already released as created or started
+		}
+	})
+
+	actions.onActionFailed(func(action *api.Action, result api.ActionResult) {
+		for _, tag := range action.GetActionRaw().GetUnitCommand().GetUnitTags() {
+			p.releaseUnit(tag)
+		}
+	})
+}
+
+// reserveBuild reserves the cost of a worker's build order until the structure is started. The
+// game only charges for the structure once the worker reaches the build site.
+func (b *Builder) reserveBuild(u Unit, train api.AbilityID, cost Cost) {
+	b.player.Reserve(Reservation{
+		Owner:   fmt.Sprintf("build:%v", u.Tag),
+		Cost:    cost,
+		Expires: b.player.gameLoop + buildReservationLoops,
+		Target:  b.tech.Produces(train),
+		Unit:    u.Tag,
+	})
+}
+
+// spend charges for an order, using a reservation for workers that have to walk to a site.
+func (b *Builder) spend(u Unit, train api.AbilityID, cost Cost) {
+	if u.IsWorker() && isStructure(b.tech.data(b.tech.Produces(train))) {
+		b.reserveBuild(u, train, cost)
+	} else {
+		b.player.Spend(cost)
+	}
+}
+
+func isStructure(data *api.UnitTypeData) bool {
+	for _, attr := range data.GetAttributes() {
+		if attr == api.Attribute_Structure {
+			return true
+		}
+	}
+	return false
+}
+
+func minUint32(a, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,89 @@
+package botutil_test
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+func TestReservations(t *testing.T) {
+	p := &botutil.Player{}
+	p.Minerals, p.Vespene, p.FoodCap, p.FoodUsed = 150, 50, 15, 14
+
+	depot := botutil.Cost{Minerals: 100}
+	p.Reserve(botutil.Reservation{Owner: "depot", Cost: depot})
+	if p.CanAfford(depot) {
+		t.Error("expected the reserved minerals to be unavailable")
+	}
+	if r := p.Reserved(); r != depot {
+		t.Errorf("got reserved %v, expected %v", r, depot)
+	}
+
+	// Reserving more than is available only holds what's there
+	p.Reserve(botutil.Reservation{Owner: "gas", Cost: botutil.Cost{Minerals: 75, Vespene: 25}})
+	if p.Minerals != 0 || p.Vespene != 25 {
+		t.Errorf("got %v minerals %v vespene, expected 0 and 25", p.Minerals, p.Vespene)
+	}
+
+	p.Release("depot")
+	if !p.CanAfford(depot) {
+		t.Error("expected released minerals to be available again")
+	}
+	p.Release("gas")
+	if p.Minerals != 150 || p.Vespene != 50 || len(p.Reservations()) != 0 {
+		t.Errorf("got %v minerals %v vespene %v reservations after releasing everything", p.Minerals, p.Vespene, len(p.Reservations()))
+	}
+}
+
+func TestReservationRelease(t *testing.T) {
+	i := &fakeInfo{player: &api.PlayerCommon{Minerals: 1000, Vespene: 1000}}
+	i.step(nil)
+	bot := botutil.NewBot(i)
+
+	reserved := func() map[string]bool {
+		owners := map[string]bool{}
+		for _, r := range bot.Reservations() {
+			owners[r.Owner] = true
+		}
+		return owners
+	}
+	bot.Reserve(botutil.Reservation{Owner: "expires", Cost: botutil.Cost{Minerals: 50}, Expires: 10})
+	bot.Reserve(botutil.Reservation{Owner: "target", Cost: botutil.Cost{Minerals: 300}, Target: zerg.Hatchery})
+	bot.Reserve(botutil.Reservation{Owner: "failed", Cost: botutil.Cost{Minerals: 200}, Unit: 1})
+
+	// Nothing is released before its time
+	i.gameLoop = 5
+	i.step(nil, &api.Unit{Tag: 1, UnitType: zerg.Drone, Alliance: api.Alliance_Self, Health: 40})
+	if r := reserved(); len(r) != 3 {
+		t.Fatalf("got reservations %v, expected all 3", r)
+	}
+
+	// Expired reservations are dropped
+	i.gameLoop = 10
+	i.step(nil, &api.Unit{Tag: 1, UnitType: zerg.Drone, Alliance: api.Alliance_Self, Health: 40})
+	if r := reserved(); r["expires"] || len(r) != 2 {
+		t.Errorf("got reservations %v, expected the expired one to be released", r)
+	}
+
+	// The target appearing releases its reservation
+	i.gameLoop = 20
+	i.step(nil,
+		&api.Unit{Tag: 1, UnitType: zerg.Drone, Alliance: api.Alliance_Self, Health: 40},
+		&api.Unit{Tag: 2, UnitType: zerg.Hatchery, Alliance: api.Alliance_Self, Health: 150, BuildProgress: 0.1})
+	if r := reserved(); r["target"] || len(r) != 1 {
+		t.Errorf("got reservations %v, expected the target's to be released", r)
+	}
+
+	// An action error for the unit releases it too
+	i.gameLoop = 30
+	i.actionErrors = []*api.ActionError{{UnitTag: 1, Result: api.ActionResult_CantBuildLocationInvalid}}
+	i.step(nil, &api.Unit{Tag: 1, UnitType: zerg.Drone, Alliance: api.Alliance_Self, Health: 40})
+	if r := reserved(); len(r) != 0 {
+		t.Errorf("got reservations %v, expected the failed one to be released", r)
+	}
+	if bot.Minerals != 1000 {
+		t.Errorf("got %v minerals, expected 1000 with nothing reserved", bot.Minerals)
+	}
+}
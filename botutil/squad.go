@@ -0,0 +1,201 @@
+package botutil
+
+import (
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+)
+
+// Squad is a group of our units that is ordered around together. Membership is kept as unit
+// tags so it persists across steps, units are only dropped when they die (not while they are
+// out of sight inside a transport or gas building). Each group order is sent as a single command
+// for all units that need it.
+type Squad struct {
+	units   *UnitContext
+	actions *Actions
+	tags    []api.UnitTag
+	offsets map[api.UnitTag]api.Vec2D
+}
+
+// NewSquad creates an empty squad and registers it to drop units that die.
+func NewSquad(units *UnitContext, actions *Actions, events *Events) *Squad {
+	s := &Squad{units: units, actions: actions}
+	events.OnUnitDestroyed(func(tag api.UnitTag, last Unit) { s.Remove(tag) })
+	return s
+}
+
+// Add adds units to the squad (units already in the squad are ignored).
+func (s *Squad) Add(units ...Unit) {
+	for _, u := range units {
+		if !u.IsNil() && !s.Has(u.Tag) {
+			s.tags = append(s.tags, u.Tag)
+		}
+	}
+}
+
+// AddUnits adds all of the units to the squad.
+func (s *Squad) AddUnits(units Units) {
+	units.Each(func(u Unit) { s.Add(u) })
+}
+
+// Remove removes the unit with the given tag from the squad.
+func (s *Squad) Remove(tag api.UnitTag) {
+	for i, t := range s.tags {
+		if t == tag {
+			s.tags = append(s.tags[:i], s.tags[i+1:]...)
+			delete(s.offsets, tag)
+			return
+		}
+	}
+}
+
+// Has returns true if the unit with the given tag is part of the squad.
+func (s *Squad) Has(tag api.UnitTag) bool {
+	for _, t := range s.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Tags returns a copy of the tags of the squad's units.
+func (s *Squad) Tags() []api.UnitTag {
+	return append([]api.UnitTag(nil), s.tags...)
+}
+
+// Len returns the number of units in the squad, including any that aren't currently observed.
+func (s *Squad) Len() int {
+	return len(s.tags)
+}
+
+// Units returns the squad's units that are in the current observation.
+func (s *Squad) Units() Units {
+	var units []Unit
+	for _, tag := range s.tags {
+		if u := s.units.UnitByTag(tag); !u.IsNil() {
+			units = append(units, u)
+		}
+	}
+	return NewUnits(units)
+}
+
+// AttackMove orders the squad to attack-move to pos. Units already attacking within tollerance
+// of pos are left alone.
+func (s *Squad) AttackMove(pos api.Point2D, tollerance float32) {
+	s.actions.UnitsOrderPos(s.Units().Choose(func(u Unit) bool {
+		return u.needsAttackMoveOrder(pos, tollerance)
+	}), ability.Attack, pos)
+}
+
+// MoveTo orders the squad to move to pos. Units already moving to within tollerance of pos are
+// left alone.
+func (s *Squad) MoveTo(pos api.Point2D, tollerance float32) {
+	s.actions.UnitsOrderPos(s.Units().Choose(func(u Unit) bool {
+		return u.needsMoveToOrder(pos, tollerance)
+	}), ability.Move, pos)
+}
+
+// Retreat pulls the squad back to pos without stopping to fight, including units that are still
+// attacking.
+func (s *Squad) Retreat(pos api.Point2D) {
+	s.MoveTo(pos, 1)
+}
+
+// Regroup moves units that are more than radius away from the squad's center back to it.
+func (s *Squad) Regroup(radius float32) {
+	units := s.Units()
+	center := units.Center()
+	s.actions.UnitsOrderPos(units.Choose(func(u Unit) bool {
+		return u.Pos2D().Distance2(center) > radius*radius && u.needsMoveToOrder(center, radius)
+	}), ability.Move, center)
+}
+
+// HoldPosition stops the squad and has each unit hold its current position.
+func (s *Squad) HoldPosition() {
+	s.actions.UnitsOrder(s.Units().Choose(func(u Unit) bool {
+		return u.IsIdle() || ability.Remap(u.Orders[0].AbilityId) != ability.HoldPosition
+	}), ability.HoldPosition)
+}
+
+// SetFormation records each observed unit's current offset from the squad's center as the
+// formation kept by HoldFormation.
+func (s *Squad) SetFormation() {
+	units := s.Units()
+	center := units.Center()
+	s.offsets = map[api.UnitTag]api.Vec2D{}
+	units.Each(func(u Unit) { s.offsets[u.Tag] = center.VecTo(u.Pos2D()) })
+}
+
+// HoldFormation moves units that are more than tollerance away from their place in the
+// formation back to it and has the rest hold position. The formation is recorded from the
+// current positions if SetFormation hasn't been called, units without a place just hold.
+func (s *Squad) HoldFormation(tollerance float32) {
+	if s.offsets == nil {
+		s.SetFormation()
+	}
+	units := s.Units()
+	center := units.Center()
+	holding := units.Choose(func(u Unit) bool {
+		if offset, ok := s.offsets[u.Tag]; ok {
+			pos := center.Add(offset)
+			if u.Pos2D().Distance2(pos) > tollerance*tollerance {
+				if u.needsMoveToOrder(pos, tollerance) {
+					s.actions.UnitOrderPos(u, ability.Move, pos)
+				}
+				return false
+			}
+		}
+		return u.IsIdle() || ability.Remap(u.Orders[0].AbilityId) != ability.HoldPosition
+	})
+	s.actions.UnitsOrder(holding, ability.HoldPosition)
+}
+
+// Center returns the average location of the squad.
+func (s *Squad) Center() api.Point2D {
+	return s.Units().Center()
+}
+
+// Spread returns the average distance of the squad's units from its center.
+func (s *Squad) Spread() float32 {
+	units := s.Units()
+	center := units.Center()
+	sum := float32(0)
+	units.Each(func(u Unit) { sum += u.Pos2D().Distance(center) })
+	if n := units.Len(); n > 0 {
+		return sum / float32(n)
+	}
+	return 0
+}
+
+// Supply returns the total supply used by the squad's observed units.
+func (s *Squad) Supply() float32 {
+	supply := float32(0)
+	s.Units().Each(func(u Unit) { supply += u.FoodRequired })
+	return supply
+}
+
+// HealthFraction returns the squad's combined health and shields as a fraction of the maximum.
+func (s *Squad) HealthFraction() float32 {
+	current, max := float32(0), float32(0)
+	s.Units().Each(func(u Unit) {
+		current += u.Health + u.Shield
+		max += u.HealthMax + u.ShieldMax
+	})
+	if max == 0 {
+		return 0
+	}
+	return current / max
+}
+
+// InCombat returns true if any unit of the squad is firing or within range of a visible enemy.
+func (s *Squad) InCombat() bool {
+	enemies := s.units.Enemy.All()
+	return s.Units().EachUntil(func(u Unit) bool {
+		if !u.WeaponReady() {
+			return true
+		}
+		return enemies.EachUntil(func(e Unit) bool {
+			return e.IsInWeaponsRange(u, 0) || u.IsInWeaponsRange(e, 0)
+		})
+	})
+}
@@ -0,0 +1,161 @@
+package botutil_test
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+func TestSquad(t *testing.T) {
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 3)
+	marines := append([]*api.Unit(nil), c.units...)
+	for _, m := range marines {
+		m.HealthMax = 45
+	}
+	marines[0].Health = 0.5 * 45
+
+	i := &fakeInfo{data: combatData}
+	i.step(nil, c.units...)
+	ctx := botutil.NewUnitContext(i, nil)
+	actions := botutil.NewActions(i)
+
+	squad := botutil.NewSquad(ctx, actions, botutil.NewEvents(i, ctx))
+	squad.AddUnits(ctx.Self.All())
+	squad.Add(ctx.UnitByTag(marines[0].Tag)) // duplicates are ignored
+	if squad.Len() != 3 {
+		t.Errorf("got %v units, expected 3", squad.Len())
+	}
+	if c := squad.Center(); c.X != 0 || c.Y != 1 {
+		t.Errorf("got center %v", c)
+	}
+	if f := squad.HealthFraction(); f < 0.83 || f > 0.84 {
+		t.Errorf("got health fraction %v", f)
+	}
+	if squad.InCombat() {
+		t.Error("expected squad not to be in combat")
+	}
+
+	// One command for the whole squad
+	squad.AttackMove(api.Point2D{X: 50, Y: 50}, 1)
+	actions.Send()
+	sent := actions.PrevActions()
+	if len(sent) != 1 || len(sent[0].GetActionRaw().GetUnitCommand().GetUnitTags()) != 3 {
+		t.Fatalf("expected a single command for 3 units, got %v", sent)
+	}
+	if a := sent[0].GetActionRaw().GetUnitCommand().AbilityId; a != ability.Attack {
+		t.Errorf("got ability %v", a)
+	}
+
+	// Units that already have the order are skipped, dead units are dropped
+	marines[1].Orders = []*api.UnitOrder{{AbilityId: ability.Attack,
+		Target: &api.UnitOrder_TargetWorldSpacePos{TargetWorldSpacePos: &api.Point{X: 50, Y: 50}}}}
+	c.add(api.Alliance_Enemy, zerg.Zergling, 1, 1)
+	i.step([]api.UnitTag{marines[2].Tag}, marines[0], marines[1], c.units[len(c.units)-1])
+	squad.AttackMove(api.Point2D{X: 50, Y: 50}, 1)
+	actions.Send()
+	sent = actions.PrevActions()
+	if tags := sent[0].GetActionRaw().GetUnitCommand().GetUnitTags(); len(tags) != 1 || tags[0] != marines[0].Tag {
+		t.Errorf("expected only the first marine to be ordered, got %v", tags)
+	}
+	if squad.Len() != 2 {
+		t.Errorf("got %v units, expected 2", squad.Len())
+	}
+	tags := squad.Tags()
+	tags[0] = 0
+	if !squad.Has(marines[0].Tag) {
+		t.Error("expected Tags to return a copy")
+	}
+	if !squad.InCombat() {
+		t.Error("expected squad to be in combat")
+	}
+}
+
+func TestSquadOrders(t *testing.T) {
+	units := append([]*api.UnitTypeData(nil), combatData.Units...)
+	marineData := *units[terran.Marine]
+	marineData.FoodRequired = 1
+	units[terran.Marine] = &marineData
+
+	c := &combatUnits{}
+	c.add(api.Alliance_Self, terran.Marine, 0, 2)
+	c.add(api.Alliance_Self, terran.Marine, 10, 1)
+	marines := append([]*api.Unit(nil), c.units...)
+
+	i := &fakeInfo{data: &api.ResponseData{Units: units}}
+	i.step(nil, marines...)
+	ctx := botutil.NewUnitContext(i, nil)
+	actions := botutil.NewActions(i)
+	squad := botutil.NewSquad(ctx, actions, botutil.NewEvents(i, ctx))
+	squad.AddUnits(ctx.Self.All())
+
+	ordered := func() (api.AbilityID, []api.UnitTag) {
+		t.Helper()
+		actions.Send()
+		sent := actions.PrevActions()
+		if len(sent) != 1 {
+			t.Fatalf("expected a single command, got %v", sent)
+		}
+		cmd := sent[0].GetActionRaw().GetUnitCommand()
+		sent[0] = nil // so a missing command isn't mistaken for this one
+		return cmd.GetAbilityId(), cmd.GetUnitTags()
+	}
+
+	// Marines at (0, 0), (0, 1) and (10, 0)
+	if c := squad.Center(); c.X != 10.0/3 || c.Y != 1.0/3 {
+		t.Errorf("got center %v", c)
+	}
+	if s := squad.Spread(); s < 4.47 || s > 4.48 {
+		t.Errorf("got spread %v, expected about 4.47", s)
+	}
+	if s := squad.Supply(); s != 3 {
+		t.Errorf("got supply %v, expected 3", s)
+	}
+
+	squad.Regroup(5)
+	if a, tags := ordered(); a != ability.Move || len(tags) != 1 || tags[0] != marines[2].Tag {
+		t.Errorf("expected only the far marine to regroup, got %v %v", a, tags)
+	}
+
+	// Retreat overrides attack orders
+	marines[0].Orders = []*api.UnitOrder{{AbilityId: ability.Attack,
+		Target: &api.UnitOrder_TargetWorldSpacePos{TargetWorldSpacePos: &api.Point{X: 20, Y: 20}}}}
+	i.step(nil, marines...)
+	squad.Retreat(api.Point2D{X: -20, Y: 0})
+	if a, tags := ordered(); a != ability.Move || len(tags) != 3 {
+		t.Errorf("expected every marine to retreat, got %v %v", a, tags)
+	}
+
+	marines[0].Orders = []*api.UnitOrder{{AbilityId: ability.HoldPosition}}
+	i.step(nil, marines...)
+	squad.HoldPosition()
+	if a, tags := ordered(); a != ability.HoldPosition || len(tags) != 2 {
+		t.Errorf("expected 2 marines to hold position, got %v %v", a, tags)
+	}
+
+	// A displaced unit moves back to its place in the formation, which follows the center
+	squad.SetFormation()
+	marines[1].Orders = []*api.UnitOrder{{AbilityId: ability.HoldPosition}}
+	marines[2].Pos = &api.Point{X: 10, Y: 6}
+	i.step(nil, marines...)
+	squad.HoldFormation(3)
+	actions.Send()
+	if sent := actions.PrevActions(); len(sent) != 1 {
+		t.Errorf("expected a single command, got %v", sent)
+	} else if cmd := sent[0].GetActionRaw().GetUnitCommand(); cmd.GetAbilityId() != ability.Move ||
+		len(cmd.GetUnitTags()) != 1 || cmd.GetUnitTags()[0] != marines[2].Tag ||
+		cmd.GetTargetWorldSpacePos().Distance2(api.Point2D{X: 10, Y: 2}) > 0.01 {
+		t.Errorf("expected the far marine to move back to (10, 2), got %v", cmd)
+	}
+
+	// Units out of sight (e.g. loaded) stay in the squad
+	i.step(nil, marines[0], marines[1])
+	i.step(nil, marines...)
+	if squad.Len() != 3 || squad.Units().Len() != 3 {
+		t.Errorf("got %v units, expected 3", squad.Len())
+	}
+}
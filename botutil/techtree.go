@@ -0,0 +1,332 @@
+package botutil
+
+import (
+	"fmt"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+	"github.com/chippydip/go-sc2ai/enums/unit"
+)
+
+// TechTree models unit tech requirements using the game's unit and ability data.
+type TechTree struct {
+	units    []*api.UnitTypeData
+	produces map[api.AbilityID]api.UnitTypeID
+}
+
+// NewTechTree builds a tech tree from the game data.
+func NewTechTree(data *api.ResponseData) *TechTree {
+	t := &TechTree{
+		units:    data.GetUnits(),
+		produces: map[api.AbilityID]api.UnitTypeID{},
+	}
+	abilities := data.GetAbilities()
+	for _, u := range t.units {
+		if u == nil || !u.Available || u.AbilityId == 0 {
+			continue
+		}
+		t.produces[u.AbilityId] = u.UnitId
+		if int(u.AbilityId) < len(abilities) {
+			if remap := abilities[u.AbilityId].GetRemapsToAbilityId(); remap != 0 {
+				if _, ok := t.produces[remap]; !ok {
+					t.produces[remap] = u.UnitId
+				}
+			}
+		}
+	}
+	return t
+}
+
+// Produces returns the unit type created by the train ability or unit.Invalid.
+func (t *TechTree) Produces(train api.AbilityID) api.UnitTypeID {
+	if target, ok := t.produces[train]; ok {
+		return target
+	}
+	return ability.Produces(train)
+}
+
+// Requirement returns the unit type that must exist before target can be built and whether it
+// must be attached to the producer as an addon. It returns unit.Invalid if there is none.
+func (t *TechTree) Requirement(target api.UnitTypeID) (api.UnitTypeID, bool) {
+	data := t.data(target)
+	if data == nil || data.TechRequirement == 0 {
+		return unit.Invalid, false
+	}
+	return data.TechRequirement, data.RequireAttached
+}
+
+// Producer returns the structure or unit that trains or morphs into target or unit.Invalid if
+// it is made by a worker or larva (or isn't known).
+func (t *TechTree) Producer(target api.UnitTypeID) api.UnitTypeID {
+	return producers[target]
+}
+
+// producers lists the structures that units are trained from and the units they morph from
+// since the game data doesn't.
+var producers = map[api.UnitTypeID]api.UnitTypeID{
+	unit.Terran_SCV:               unit.Terran_CommandCenter,
+	unit.Terran_OrbitalCommand:    unit.Terran_CommandCenter,
+	unit.Terran_PlanetaryFortress: unit.Terran_CommandCenter,
+	unit.Terran_Marine:            unit.Terran_Barracks,
+	unit.Terran_Reaper:            unit.Terran_Barracks,
+	unit.Terran_Marauder:          unit.Terran_Barracks,
+	unit.Terran_Ghost:             unit.Terran_Barracks,
+	unit.Terran_Hellion:           unit.Terran_Factory,
+	unit.Terran_HellionTank:       unit.Terran_Factory,
+	unit.Terran_WidowMine:         unit.Terran_Factory,
+	unit.Terran_SiegeTank:         unit.Terran_Factory,
+	unit.Terran_Cyclone:           unit.Terran_Factory,
+	unit.Terran_Thor:              unit.Terran_Factory,
+	unit.Terran_VikingFighter:     unit.Terran_Starport,
+	unit.Terran_Medivac:           unit.Terran_Starport,
+	unit.Terran_Liberator:         unit.Terran_Starport,
+	unit.Terran_Raven:             unit.Terran_Starport,
+	unit.Terran_Banshee:           unit.Terran_Starport,
+	unit.Terran_Battlecruiser:     unit.Terran_Starport,
+
+	unit.Protoss_Probe:       unit.Protoss_Nexus,
+	unit.Protoss_Mothership:  unit.Protoss_Nexus,
+	unit.Protoss_Zealot:      unit.Protoss_Gateway,
+	unit.Protoss_Stalker:     unit.Protoss_Gateway,
+	unit.Protoss_Sentry:      unit.Protoss_Gateway,
+	unit.Protoss_Adept:       unit.Protoss_Gateway,
+	unit.Protoss_HighTemplar: unit.Protoss_Gateway,
+	unit.Protoss_DarkTemplar: unit.Protoss_Gateway,
+	unit.Protoss_Observer:    unit.Protoss_RoboticsFacility,
+	unit.Protoss_WarpPrism:   unit.Protoss_RoboticsFacility,
+	unit.Protoss_Immortal:    unit.Protoss_RoboticsFacility,
+	unit.Protoss_Colossus:    unit.Protoss_RoboticsFacility,
+	unit.Protoss_Disruptor:   unit.Protoss_RoboticsFacility,
+	unit.Protoss_Phoenix:     unit.Protoss_Stargate,
+	unit.Protoss_Oracle:      unit.Protoss_Stargate,
+	unit.Protoss_VoidRay:     unit.Protoss_Stargate,
+	unit.Protoss_Tempest:     unit.Protoss_Stargate,
+	unit.Protoss_Carrier:     unit.Protoss_Stargate,
+	unit.Protoss_WarpGate:    unit.Protoss_Gateway,
+	unit.Protoss_Archon:      unit.Protoss_HighTemplar,
+
+	unit.Zerg_Queen:             unit.Zerg_Hatchery,
+	unit.Zerg_Lair:              unit.Zerg_Hatchery,
+	unit.Zerg_Hive:              unit.Zerg_Lair,
+	unit.Zerg_GreaterSpire:      unit.Zerg_Spire,
+	unit.Zerg_Baneling:          unit.Zerg_Zergling,
+	unit.Zerg_Ravager:           unit.Zerg_Roach,
+	unit.Zerg_LurkerMP:          unit.Zerg_Hydralisk,
+	unit.Zerg_BroodLord:         unit.Zerg_Corruptor,
+	unit.Zerg_Overseer:          unit.Zerg_Overlord,
+	unit.Zerg_OverlordTransport: unit.Zerg_Overlord,
+}
+
+// Requirements returns every structure (or unit to morph from) needed for target, starting with
+// its direct tech requirement and producer, followed by what those need in turn.
+func (t *TechTree) Requirements(target api.UnitTypeID) []api.UnitTypeID {
+	var chain []api.UnitTypeID
+	for i, next := -1, target; i < len(chain); i++ {
+		if i >= 0 {
+			next = chain[i]
+		}
+		for _, req := range t.needs(next) {
+			if req != target && !containsType(chain, req) { // guard against cycles in bad data
+				chain = append(chain, req)
+			}
+		}
+	}
+	return chain
+}
+
+// needs returns the direct tech requirement and producer of target, if any.
+func (t *TechTree) needs(target api.UnitTypeID) []api.UnitTypeID {
+	var needs []api.UnitTypeID
+	if req, _ := t.Requirement(target); req != unit.Invalid {
+		needs = append(needs, req)
+	}
+	if p := t.Producer(target); p != unit.Invalid && !containsType(needs, p) {
+		needs = append(needs, p)
+	}
+	return needs
+}
+
+func containsType(types []api.UnitTypeID, unitType api.UnitTypeID) bool {
+	for _, t := range types {
+		if t == unitType {
+			return true
+		}
+	}
+	return false
+}
+
+// Satisfies returns true if having a unit of type have meets a requirement for type required.
+// Tech aliases are considered, so a Lair satisfies a Hatchery requirement and a BarracksTechLab
+// satisfies a TechLab requirement.
+func (t *TechTree) Satisfies(have, required api.UnitTypeID) bool {
+	if have == required {
+		return true
+	}
+	data := t.data(have)
+	if data == nil {
+		return false
+	}
+	if data.UnitAlias != 0 && data.UnitAlias != have && t.Satisfies(data.UnitAlias, required) {
+		return true
+	}
+	for _, alias := range data.TechAlias {
+		if alias == required {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TechTree) data(unitType api.UnitTypeID) *api.UnitTypeData {
+	if int(unitType) < len(t.units) {
+		return t.units[unitType]
+	}
+	return nil
+}
+
+// BuildStatus describes whether something can be built right now and if not, why not.
+type BuildStatus int
+
+// BuildStatus values in the order they are checked.
+const (
+	BuildOK          BuildStatus = iota
+	BuildUnsupported             // the ability doesn't produce a unit
+	BuildMissingStructure
+	BuildMissingAddon
+	BuildNeedsSupply
+	BuildNeedsMinerals
+	BuildNeedsVespene
+	BuildNoIdleProducer
+)
+
+func (s BuildStatus) String() string {
+	switch s {
+	case BuildOK:
+		return "ok"
+	case BuildUnsupported:
+		return "unsupported ability"
+	case BuildMissingStructure:
+		return "missing structure"
+	case BuildMissingAddon:
+		return "missing addon"
+	case BuildNeedsSupply:
+		return "not enough supply"
+	case BuildNeedsMinerals:
+		return "not enough minerals"
+	case BuildNeedsVespene:
+		return "not enough vespene"
+	case BuildNoIdleProducer:
+		return "no idle producer"
+	}
+	return fmt.Sprintf("BuildStatus(%d)", int(s))
+}
+
+// BuildCheck is the result of Builder.CanBuild.
+type BuildCheck struct {
+	Status   BuildStatus
+	Target   api.UnitTypeID // unit that would be produced
+	Requires api.UnitTypeID // missing structure or addon type
+	Cost     Cost
+}
+
+// OK returns true if the unit can be built right now.
+func (c BuildCheck) OK() bool {
+	return c.Status == BuildOK
+}
+
+func (c BuildCheck) String() string {
+	if c.Requires != unit.Invalid {
+		return fmt.Sprintf("%v: %v (%v)", unit.String(c.Target), c.Status, unit.String(c.Requires))
+	}
+	return fmt.Sprintf("%v: %v", unit.String(c.Target), c.Status)
+}
+
+// Tech returns the tech tree used by the builder.
+func (b *Builder) Tech() *TechTree {
+	return b.tech
+}
+
+// CanBuild checks if a producer of the given type can use the train ability right now. If not,
+// the result explains the first unmet requirement.
+func (b *Builder) CanBuild(producer api.UnitTypeID, train api.AbilityID) BuildCheck {
+	check := BuildCheck{Target: b.tech.Produces(train)}
+	if check.Target == unit.Invalid {
+		check.Status = BuildUnsupported
+		return check
+	}
+
+	req, attached := b.tech.Requirement(check.Target)
+	if req != unit.Invalid && !attached && !b.HasTech(req) {
+		check.Status, check.Requires = BuildMissingStructure, req
+		return check
+	}
+
+	producers := b.units.Self[producer]
+	if attached {
+		producers = producers.Choose(func(u Unit) bool { return b.hasAddon(u, req) })
+		if producers.Len() == 0 {
+			check.Status, check.Requires = BuildMissingAddon, req
+			return check
+		}
+	}
+
+	check.Cost = b.ProductionCost(producer, train)
+	switch p := b.player; {
+	case check.Cost.Food > 0 && p.FoodCap < p.FoodUsed+check.Cost.Food:
+		check.Status = BuildNeedsSupply
+	case p.Minerals < check.Cost.Minerals:
+		check.Status = BuildNeedsMinerals
+	case p.Vespene < check.Cost.Vespene:
+		check.Status = BuildNeedsVespene
+	case producers.Choose(b.isIdleProducer).Len() == 0:
+		check.Status = BuildNoIdleProducer
+	}
+	return check
+}
+
+// HasTech returns true if we have a completed unit that satisfies the required type.
+func (b *Builder) HasTech(required api.UnitTypeID) bool {
+	for unitType, units := range b.units.Self {
+		if b.tech.Satisfies(unitType, required) && units.IsBuilt().Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NextToUnlock returns the next missing requirement or producer that should be built to unlock
+// target or unit.Invalid if all of them are already there. Addon requirements are returned as
+// the generic addon type (e.g. TechLab).
+func (b *Builder) NextToUnlock(target api.UnitTypeID) api.UnitTypeID {
+	next := unit.Invalid
+	chain := b.tech.Requirements(target)
+	for i := len(chain) - 1; i >= 0; i-- {
+		req := chain[i]
+		if b.HasTech(req) {
+			continue
+		}
+		next = req
+		unlocked := true
+		for _, r := range b.tech.needs(req) {
+			unlocked = unlocked && b.HasTech(r)
+		}
+		if unlocked {
+			break // deeper requirements come later in the chain, so this one can be built now
+		}
+	}
+	return next
+}
+
+// hasAddon returns true if the producer has a completed addon satisfying the required type.
+func (b *Builder) hasAddon(u Unit, required api.UnitTypeID) bool {
+	if u.AddOnTag == 0 {
+		return false
+	}
+	addon := b.units.UnitByTag(u.AddOnTag)
+	return !addon.IsNil() && addon.IsBuilt() && b.tech.Satisfies(addon.UnitType, required)
+}
+
+// isIdleProducer returns true if the unit can accept a new production order this step.
+func (b *Builder) isIdleProducer(u Unit) bool {
+	return !(u.BuildProgress < 1 || (len(u.Orders) > 0 && u.IsStructure()) || b.used[u.Tag])
+}
@@ -0,0 +1,195 @@
+package botutil_test
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+	"github.com/chippydip/go-sc2ai/enums/unit"
+	"github.com/chippydip/go-sc2ai/enums/zerg"
+)
+
+func TestCanBuild(t *testing.T) {
+	structure := []api.Attribute{api.Attribute_Structure}
+	units := make([]*api.UnitTypeData, 1000)
+	units[zerg.Larva] = &api.UnitTypeData{UnitId: zerg.Larva, Available: true}
+	units[zerg.Drone] = &api.UnitTypeData{UnitId: zerg.Drone, Available: true, MineralCost: 50, FoodRequired: 1}
+	units[zerg.Hatchery] = &api.UnitTypeData{UnitId: zerg.Hatchery, Available: true, Attributes: structure}
+	units[zerg.Lair] = &api.UnitTypeData{UnitId: zerg.Lair, Available: true, Attributes: structure, TechAlias: []api.UnitTypeID{zerg.Hatchery}}
+	units[zerg.SpawningPool] = &api.UnitTypeData{UnitId: zerg.SpawningPool, Available: true, Attributes: structure,
+		AbilityId: ability.Build_SpawningPool, TechRequirement: zerg.Hatchery}
+	units[zerg.RoachWarren] = &api.UnitTypeData{UnitId: zerg.RoachWarren, Available: true, Attributes: structure,
+		AbilityId: ability.Build_RoachWarren, TechRequirement: zerg.SpawningPool}
+	units[zerg.Roach] = &api.UnitTypeData{UnitId: zerg.Roach, Available: true, MineralCost: 75, VespeneCost: 25, FoodRequired: 2,
+		AbilityId: ability.Train_Roach, TechRequirement: zerg.RoachWarren}
+
+	i := &fakeInfo{data: &api.ResponseData{Units: units}}
+	var tag api.UnitTag
+	add := func(unitType api.UnitTypeID, progress float32) *api.Unit {
+		tag++
+		return &api.Unit{Tag: tag, UnitType: unitType, Alliance: api.Alliance_Self, BuildProgress: progress, Pos: &api.Point{}}
+	}
+	larva, warren := add(zerg.Larva, 1), add(zerg.RoachWarren, 0.5)
+	i.step(nil, add(zerg.Lair, 1), larva)
+
+	player := &botutil.Player{}
+	player.Minerals, player.Vespene, player.FoodCap, player.FoodUsed = 1000, 1000, 200, 10
+	b := botutil.NewBuilder(i, player, botutil.NewUnitContext(i, nil))
+
+	if !b.HasTech(zerg.Hatchery) {
+		t.Error("expected Lair to satisfy Hatchery")
+	}
+	expect := func(status botutil.BuildStatus, requires, next api.UnitTypeID) {
+		t.Helper()
+		check := b.CanBuild(zerg.Larva, ability.Train_Roach)
+		if check.Status != status || check.Requires != requires {
+			t.Errorf("got %v, expected %v (%v)", check, status, unit.String(requires))
+		}
+		if n := b.NextToUnlock(zerg.Roach); n != next {
+			t.Errorf("got next %v, expected %v", unit.String(n), unit.String(next))
+		}
+	}
+	expect(botutil.BuildMissingStructure, zerg.RoachWarren, zerg.SpawningPool)
+
+	owned := []*api.Unit{add(zerg.Lair, 1), larva, add(zerg.SpawningPool, 1), warren}
+	i.step(nil, owned...)
+	expect(botutil.BuildMissingStructure, zerg.RoachWarren, zerg.RoachWarren)
+
+	warren.BuildProgress = 1
+	i.step(nil, owned...)
+	expect(botutil.BuildOK, unit.Invalid, unit.Invalid)
+
+	player.FoodUsed = 199
+	expect(botutil.BuildNeedsSupply, unit.Invalid, unit.Invalid)
+	player.FoodUsed = 10
+	player.Minerals = 50
+	expect(botutil.BuildNeedsMinerals, unit.Invalid, unit.Invalid)
+	player.Minerals, player.Vespene = 100, 0
+	expect(botutil.BuildNeedsVespene, unit.Invalid, unit.Invalid)
+	player.Vespene = 100
+
+	larva.BuildProgress = 0
+	i.step(nil, owned...)
+	expect(botutil.BuildNoIdleProducer, unit.Invalid, unit.Invalid)
+}
+
+func TestNextToUnlockProducer(t *testing.T) {
+	structure := []api.Attribute{api.Attribute_Structure}
+	units := make([]*api.UnitTypeData, 1000)
+	units[terran.CommandCenter] = &api.UnitTypeData{UnitId: terran.CommandCenter, Available: true, Attributes: structure}
+	units[terran.SupplyDepot] = &api.UnitTypeData{UnitId: terran.SupplyDepot, Available: true, Attributes: structure}
+	units[terran.Barracks] = &api.UnitTypeData{UnitId: terran.Barracks, Available: true, Attributes: structure,
+		AbilityId: ability.Build_Barracks, TechRequirement: terran.SupplyDepot}
+	units[terran.Marine] = &api.UnitTypeData{UnitId: terran.Marine, Available: true, MineralCost: 50, FoodRequired: 1,
+		AbilityId: ability.Train_Marine}
+
+	i := &fakeInfo{data: &api.ResponseData{Units: units}}
+	owned := []*api.Unit{{Tag: 1, UnitType: terran.CommandCenter, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}}}
+	i.step(nil, owned...)
+
+	b := botutil.NewBuilder(i, &botutil.Player{}, botutil.NewUnitContext(i, nil))
+	if r := b.Tech().Requirements(terran.Marine); len(r) != 2 || r[0] != terran.Barracks || r[1] != terran.SupplyDepot {
+		t.Errorf("got requirements %v, expected Barracks and SupplyDepot", r)
+	}
+	if n := b.NextToUnlock(terran.Marine); n != terran.SupplyDepot {
+		t.Errorf("got next %v, expected SupplyDepot", unit.String(n))
+	}
+
+	owned = append(owned, &api.Unit{Tag: 2, UnitType: terran.SupplyDepot, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}})
+	i.step(nil, owned...)
+	if n := b.NextToUnlock(terran.Marine); n != terran.Barracks {
+		t.Errorf("got next %v, expected Barracks", unit.String(n))
+	}
+
+	owned = append(owned, &api.Unit{Tag: 3, UnitType: terran.Barracks, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}})
+	i.step(nil, owned...)
+	if n := b.NextToUnlock(terran.Marine); n != unit.Invalid {
+		t.Errorf("got next %v, expected nothing", unit.String(n))
+	}
+
+	// Abilities only known from the game data are costed the same way
+	units[terran.Marauder] = &api.UnitTypeData{UnitId: terran.Marauder, Available: true, MineralCost: 100, VespeneCost: 25,
+		FoodRequired: 2, AbilityId: 99999}
+	b = botutil.NewBuilder(i, &botutil.Player{}, botutil.NewUnitContext(i, nil))
+	if check := b.CanBuild(terran.Barracks, 99999); check.Target != terran.Marauder || check.Cost.Vespene != 25 {
+		t.Errorf("got %v with cost %+v", check, check.Cost)
+	}
+
+	// Abilities that don't produce a unit can't be checked
+	if check := b.CanBuild(terran.Barracks, ability.Research_Stimpack); check.Status != botutil.BuildUnsupported {
+		t.Errorf("got %v, expected %v", check.Status, botutil.BuildUnsupported)
+	}
+}
+
+func TestNextToUnlockMorph(t *testing.T) {
+	structure := []api.Attribute{api.Attribute_Structure}
+	units := make([]*api.UnitTypeData, 1000)
+	units[zerg.Hatchery] = &api.UnitTypeData{UnitId: zerg.Hatchery, Available: true, Attributes: structure}
+	units[zerg.SpawningPool] = &api.UnitTypeData{UnitId: zerg.SpawningPool, Available: true, Attributes: structure,
+		AbilityId: ability.Build_SpawningPool, TechRequirement: zerg.Hatchery}
+	units[zerg.BanelingNest] = &api.UnitTypeData{UnitId: zerg.BanelingNest, Available: true, Attributes: structure,
+		AbilityId: ability.Build_BanelingNest, TechRequirement: zerg.SpawningPool}
+	units[zerg.Zergling] = &api.UnitTypeData{UnitId: zerg.Zergling, Available: true, FoodRequired: 0.5,
+		AbilityId: ability.Train_Zergling, TechRequirement: zerg.SpawningPool}
+	units[zerg.Baneling] = &api.UnitTypeData{UnitId: zerg.Baneling, Available: true, FoodRequired: 0.5,
+		AbilityId: ability.Train_Baneling, TechRequirement: zerg.BanelingNest}
+
+	i := &fakeInfo{data: &api.ResponseData{Units: units}}
+	owned := []*api.Unit{
+		{Tag: 1, UnitType: zerg.Hatchery, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}},
+		{Tag: 2, UnitType: zerg.SpawningPool, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}},
+		{Tag: 3, UnitType: zerg.BanelingNest, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}},
+	}
+	i.step(nil, owned...)
+	b := botutil.NewBuilder(i, &botutil.Player{}, botutil.NewUnitContext(i, nil))
+
+	// Banelings are morphed from zerglings
+	if p := b.Tech().Producer(zerg.Baneling); p != zerg.Zergling {
+		t.Errorf("got producer %v, expected Zergling", unit.String(p))
+	}
+	if n := b.NextToUnlock(zerg.Baneling); n != zerg.Zergling {
+		t.Errorf("got next %v, expected Zergling", unit.String(n))
+	}
+
+	owned = append(owned, &api.Unit{Tag: 4, UnitType: zerg.Zergling, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}})
+	i.step(nil, owned...)
+	if n := b.NextToUnlock(zerg.Baneling); n != unit.Invalid {
+		t.Errorf("got next %v, expected nothing", unit.String(n))
+	}
+}
+
+func TestBuildUnitAtRequirement(t *testing.T) {
+	structure := []api.Attribute{api.Attribute_Structure}
+	units := make([]*api.UnitTypeData, 1000)
+	units[terran.SCV] = &api.UnitTypeData{UnitId: terran.SCV, Available: true, FoodRequired: 1}
+	units[terran.SupplyDepot] = &api.UnitTypeData{UnitId: terran.SupplyDepot, Available: true, Attributes: structure,
+		MineralCost: 100, AbilityId: ability.Build_SupplyDepot}
+	units[terran.Barracks] = &api.UnitTypeData{UnitId: terran.Barracks, Available: true, Attributes: structure,
+		MineralCost: 150, AbilityId: ability.Build_Barracks, TechRequirement: terran.SupplyDepot}
+
+	i := &fakeInfo{
+		data:      &api.ResponseData{Units: units},
+		gameInfo:  &api.ResponseGameInfo{StartRaw: &api.StartRaw{StartLocations: []*api.Point2D{{X: 100, Y: 100}}}},
+		player:    &api.PlayerCommon{Minerals: 1000, FoodCap: 15, FoodUsed: 12},
+		abilities: map[api.UnitTag][]api.AbilityID{1: {ability.Build_Barracks}},
+	}
+	scv := &api.Unit{Tag: 1, UnitType: terran.SCV, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{}}
+	i.step(nil, scv)
+	bot := botutil.NewBot(i)
+
+	sent := func() int {
+		bot.Actions.Send()
+		return len(bot.Actions.PrevActions())
+	}
+	if bot.BuildUnitAt(terran.SCV, ability.Build_Barracks, api.Point2D{X: 10, Y: 10}) || sent() != 0 {
+		t.Error("expected Barracks to need a SupplyDepot")
+	}
+
+	depot := &api.Unit{Tag: 2, UnitType: terran.SupplyDepot, Alliance: api.Alliance_Self, BuildProgress: 1, Pos: &api.Point{X: 5}}
+	i.step(nil, scv, depot)
+	if !bot.BuildUnitAt(terran.SCV, ability.Build_Barracks, api.Point2D{X: 10, Y: 10}) || sent() != 1 {
+		t.Error("expected to build Barracks")
+	}
+}
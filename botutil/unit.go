@@ -172,7 +172,8 @@ func (u Unit) AirWeaponDamage() float32 {
 	return u.weaponDamage(api.Weapon_Air)
 }
 
-// WeaponDamage returns damage per shot the unit can do to the given target.
+// WeaponDamage returns damage per shot the unit can do to the given target. It ignores bonuses,
+// armor and upgrades, see DamageAgainst.
 func (u Unit) WeaponDamage(target Unit) float32 {
 	if target.IsFlying {
 		return u.weaponDamage(api.Weapon_Air)
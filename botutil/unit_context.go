@@ -1,7 +1,7 @@
 package botutil
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/client"
@@ -45,7 +45,11 @@ type UnitContext struct {
 	Enemy   enemy
 	Neutral neutral
 
-	bot *Bot
+	// EnemyUpgrades estimates the upgrades of units we don't own (defaults to ObservedUpgrades).
+	EnemyUpgrades UpgradeEstimate
+
+	info client.AgentInfo
+	bot  *Bot
 
 	dummy Units
 }
@@ -58,6 +62,7 @@ func NewUnitContext(info client.AgentInfo, bot *Bot) *UnitContext {
 		Ally:    ally{},
 		Enemy:   enemy{},
 		Neutral: neutral{},
+		info:    info,
 		bot:     bot,
 	}
 	ctx.dummy = Units{raw: []Unit{Unit{ctx: ctx}}}
@@ -108,7 +113,7 @@ func (ctx *UnitContext) update(info client.AgentInfo) {
 	}
 	available := info.Query(api.RequestQuery{Abilities: query})
 	if len(available.Abilities) != len(ctx.raw) {
-		log.Panicf("Missing ability responses, expected: %v got: %v", len(ctx.raw), len(available.Abilities))
+		panic(fmt.Sprintf("Missing ability responses, expected: %v got: %v", len(ctx.raw), len(available.Abilities)))
 	}
 
 	// Allocate a new array for wrapped unit objects
@@ -1,7 +1,9 @@
 package client
 
 import (
-	"log"
+	"context"
+	"encoding/binary"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -44,12 +46,26 @@ type AgentInfo interface {
 	ClearDebugDraw()
 	LeaveGame()
 	SaveReplay(path string)
+	QuickSave() error
+	QuickLoad() error
+	RestartGame() error
 
 	OnBeforeStep(func())
 	OnObservation(func())
 	OnAfterStep(func())
+	OnDisconnect(func(error))
+	OnReconnect(func())
 
 	SetPerfInterval(steps uint32)
+	SetMetricsSink(sinks ...MetricsSink)
+	Logger() *slog.Logger
+
+	GetClientErrors() []ClientError
+	GetProtocolErrors() []string
+	ClearClientErrors()
+	ClearProtocolErrors()
+	ErrorIf(condition bool, err error) bool
+	OnError(func(ClientError))
 }
 
 // IsRealtime returns true if the bot was launched in realtime mode.
@@ -95,62 +111,200 @@ func (c *Client) HasUpgrade(upgrade api.UpgradeID) bool {
 
 // Query ...
 func (c *Client) Query(query api.RequestQuery) *api.ResponseQuery {
-	resp, err := c.connection.query(query)
+	resp, err := c.QueryContext(context.Background(), query)
 	if err != nil {
-		log.Print(err)
+		c.recordError("Query", err)
 		return nil
 	}
 	return resp
 }
 
-// SendActions ...
+// QueryContext is like Query but gives up when ctx is done and returns any error.
+func (c *Client) QueryContext(ctx context.Context, query api.RequestQuery) (*api.ResponseQuery, error) {
+	return c.connection.queryContext(ctx, query)
+}
+
+// SendActions sends the actions to the game, splitting the batch into several requests if it
+// would exceed MaxMessageSize. The results are aligned with the actions, those that couldn't be
+// sent because a request failed are reported as ActionResult_Error.
 func (c *Client) SendActions(actions []*api.Action) []api.ActionResult {
+	results, err := c.SendActionsContext(context.Background(), actions)
+	if err != nil {
+		c.recordError("Action", err)
+	}
+	return results
+}
+
+// SendActionsContext is like SendActions but gives up when ctx is done and returns any error.
+func (c *Client) SendActionsContext(ctx context.Context, actions []*api.Action) ([]api.ActionResult, error) {
 	c.actions += len(actions)
 
 	if c.replayInfo != nil {
-		return nil // ignore actions in a replay
+		return nil, nil // ignore actions in a replay
 	}
 
-	resp, err := c.connection.action(api.RequestAction{
-		Actions: actions,
-	})
-	if err != nil {
-		log.Print(err)
-		return nil
+	// Keep results aligned with actions even if a batch fails or reports the wrong number of results
+	results := make([]api.ActionResult, 0, len(actions))
+	for _, batch := range splitBatch(actions) {
+		resp, err := c.connection.actionContext(ctx, api.RequestAction{
+			Actions: batch,
+		})
+		if err != nil {
+			for len(results) < len(actions) {
+				results = append(results, api.ActionResult_Error) // not sent
+			}
+			return results, err
+		}
+		r := resp.GetResult()
+		if len(r) > len(batch) {
+			r = r[:len(batch)]
+		}
+		results = append(results, r...)
+		results = append(results, make([]api.ActionResult, len(batch)-len(r))...)
 	}
-	return resp.GetResult()
+	return results, nil
 }
 
 // SendObserverActions ...
 func (c *Client) SendObserverActions(obsActions []*api.ObserverAction) {
+	if err := c.SendObserverActionsContext(context.Background(), obsActions); err != nil {
+		c.recordError("ObsAction", err)
+	}
+}
+
+// SendObserverActionsContext is like SendObserverActions but gives up when ctx is done and returns any error.
+func (c *Client) SendObserverActionsContext(ctx context.Context, obsActions []*api.ObserverAction) error {
 	c.observerActions += len(obsActions)
 
 	if c.replayInfo == nil {
-		return // ignore observer actions in a normal game
+		return nil // ignore observer actions in a normal game
 	}
 
-	c.connection.obsAction(api.RequestObserverAction{
+	_, err := c.connection.obsActionContext(ctx, api.RequestObserverAction{
 		Actions: obsActions,
 	})
+	return err
 }
 
-// SendDebugCommands ...
+// SendDebugCommands sends the commands to the game, splitting the batch into several requests
+// if it would exceed MaxMessageSize. Each request with a draw command replaces everything drawn
+// before, so all draw commands are merged into a single one in the last request. Draw primitives
+// that don't fit in one request are dropped.
 func (c *Client) SendDebugCommands(commands []*api.DebugCommand) {
+	if err := c.SendDebugCommandsContext(context.Background(), commands); err != nil {
+		c.recordError("Debug", err)
+	}
+}
+
+// SendDebugCommandsContext is like SendDebugCommands but gives up when ctx is done and returns any error.
+func (c *Client) SendDebugCommandsContext(ctx context.Context, commands []*api.DebugCommand) error {
+	return c.sendDebugCommands(ctx, commands, true)
+}
+
+// sendDebugCommands sends the commands, merging all draws into one. The merged draw is kept in
+// lastDraw if keepDraw is set so it can be re-sent along with the perf stats (see drawPerf).
+func (c *Client) sendDebugCommands(ctx context.Context, commands []*api.DebugCommand, keepDraw bool) error {
 	c.debugCommands += len(commands)
 
-	c.lastDraw = nil
+	var draw *api.DebugDraw
+	others := make([]*api.DebugCommand, 0, len(commands))
 	for _, cmd := range commands {
-		if _, ok := cmd.Command.(*api.DebugCommand_Draw); ok {
-			c.lastDraw = append(c.lastDraw, cmd)
+		d, ok := cmd.Command.(*api.DebugCommand_Draw)
+		if !ok {
+			others = append(others, cmd)
+			continue
+		}
+		if draw == nil {
+			draw = &api.DebugDraw{}
+		}
+		draw.Text = append(draw.Text, d.Draw.GetText()...)
+		draw.Lines = append(draw.Lines, d.Draw.GetLines()...)
+		draw.Boxes = append(draw.Boxes, d.Draw.GetBoxes()...)
+		draw.Spheres = append(draw.Spheres, d.Draw.GetSpheres()...)
+	}
+
+	if keepDraw {
+		c.lastDraw = nil
+	}
+	if draw != nil {
+		if dropped := fitDraw(draw); dropped > 0 {
+			c.connection.log().Warn("Debug draw too large", "dropped", dropped)
+		}
+		cmd := &api.DebugCommand{Command: &api.DebugCommand_Draw{Draw: draw}}
+		if keepDraw {
+			c.lastDraw = []*api.DebugCommand{cmd}
+		}
+		others = append(others, cmd)
+
+		if c.debugDraw == nil {
+			c.debugDraw = deferCleanup(func() { c.ClearDebugDraw() })
 		}
 	}
-	if c.debugDraw == nil && len(c.lastDraw) > 0 {
-		c.debugDraw = deferCleanup(func() { c.ClearDebugDraw() })
+	if len(others) == 0 {
+		return nil
 	}
 
-	c.connection.debug(api.RequestDebug{
-		Debug: commands,
-	})
+	for _, batch := range splitBatch(others) {
+		if _, err := c.connection.debugContext(ctx, api.RequestDebug{
+			Debug: batch,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchOverhead is reserved for the request envelope when splitting batches.
+const batchOverhead = 64
+
+// splitBatch splits items into consecutive batches that each fit within MaxMessageSize once
+// serialized. An item that is too large by itself still gets its own batch (and will fail).
+func splitBatch[T interface{ Size() int }](items []T) [][]T {
+	limit := MaxMessageSize - batchOverhead
+
+	var batches [][]T
+	start, size := 0, 0
+	for i, item := range items {
+		n := item.Size() + 1 + binary.MaxVarintLen32 // field tag and length prefix
+		if i > start && size+n > limit {
+			batches = append(batches, items[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	return append(batches, items[start:])
+}
+
+// fitDraw drops the draw primitives (spheres first, then boxes, lines and text) that would make
+// the draw command too large for a single request and returns how many were dropped.
+func fitDraw(draw *api.DebugDraw) int {
+	limit := MaxMessageSize - batchOverhead - 2*(1+binary.MaxVarintLen32) // command and draw fields
+	size := draw.Size()
+	if size <= limit {
+		return 0
+	}
+
+	count := func() int { return len(draw.Text) + len(draw.Lines) + len(draw.Boxes) + len(draw.Spheres) }
+	before := count()
+	draw.Spheres = trim(draw.Spheres, &size, limit)
+	draw.Boxes = trim(draw.Boxes, &size, limit)
+	draw.Lines = trim(draw.Lines, &size, limit)
+	draw.Text = trim(draw.Text, &size, limit)
+	return before - count()
+}
+
+// trim removes items from the end until size is within limit, updating size as it goes.
+func trim[T interface{ Size() int }](items []T, size *int, limit int) []T {
+	for len(items) > 0 && *size > limit {
+		*size -= items[len(items)-1].Size() + 1 + varintLen(items[len(items)-1].Size())
+		items = items[:len(items)-1]
+	}
+	return items
+}
+
+func varintLen(n int) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], uint64(n))
 }
 
 func deferCleanup(cleanup func()) chan struct{} {
@@ -189,19 +343,21 @@ func (c *Client) ClearDebugDraw() {
 
 // LeaveGame ...
 func (c *Client) LeaveGame() {
-	c.connection.leaveGame(api.RequestLeaveGame{})
+	if _, err := c.connection.leaveGame(api.RequestLeaveGame{}); err != nil {
+		c.recordError("LeaveGame", err)
+	}
 }
 
 // SaveReplay ...
 func (c *Client) SaveReplay(path string) {
 	responseSaveReplay, err := c.connection.saveReplay(api.RequestSaveReplay{})
 	if err != nil {
-		log.Print(err)
+		c.recordError("SaveReplay", err)
 		return
 	}
 	err = os.WriteFile(path, responseSaveReplay.GetData(), 0644)
 	if err != nil {
-		log.Print(err)
+		c.recordError("SaveReplay", err)
 	}
 }
 
@@ -229,6 +385,13 @@ func (c *Client) OnAfterStep(callback func()) {
 	}
 }
 
+// ClearCallbacks removes every callback registered with the On* methods. The runner calls it
+// before each episode so an agent from a previous game stops receiving updates.
+func (c *Client) ClearCallbacks() {
+	c.beforeStep, c.subStep, c.afterStep = nil, nil, nil
+	c.disconnect, c.reconnected, c.onError = nil, nil, nil
+}
+
 // SetPerfInterval determines how often perfornace data will be updated. Values
 // less than or equal to 0 will disable display (defalts to zero).
 func (c *Client) SetPerfInterval(steps uint32) {
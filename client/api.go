@@ -2,11 +2,17 @@
 package client
 
 import (
+	"context"
+
 	"github.com/chippydip/go-sc2ai/api"
 )
 
 func (c *connection) createGame(createGame api.RequestCreateGame) (*api.ResponseCreateGame, error) {
-	r, err := c.request(&api.Request{
+	return c.createGameContext(context.Background(), createGame)
+}
+
+func (c *connection) createGameContext(ctx context.Context, createGame api.RequestCreateGame) (*api.ResponseCreateGame, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_CreateGame{
 			CreateGame: &createGame,
 		},
@@ -15,7 +21,11 @@ func (c *connection) createGame(createGame api.RequestCreateGame) (*api.Response
 }
 
 func (c *connection) joinGame(joinGame api.RequestJoinGame) (*api.ResponseJoinGame, error) {
-	r, err := c.request(&api.Request{
+	return c.joinGameContext(context.Background(), joinGame)
+}
+
+func (c *connection) joinGameContext(ctx context.Context, joinGame api.RequestJoinGame) (*api.ResponseJoinGame, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_JoinGame{
 			JoinGame: &joinGame,
 		},
@@ -24,7 +34,11 @@ func (c *connection) joinGame(joinGame api.RequestJoinGame) (*api.ResponseJoinGa
 }
 
 func (c *connection) restartGame(restartGame api.RequestRestartGame) (*api.ResponseRestartGame, error) {
-	r, err := c.request(&api.Request{
+	return c.restartGameContext(context.Background(), restartGame)
+}
+
+func (c *connection) restartGameContext(ctx context.Context, restartGame api.RequestRestartGame) (*api.ResponseRestartGame, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_RestartGame{
 			RestartGame: &restartGame,
 		},
@@ -33,7 +47,11 @@ func (c *connection) restartGame(restartGame api.RequestRestartGame) (*api.Respo
 }
 
 func (c *connection) startReplay(startReplay api.RequestStartReplay) (*api.ResponseStartReplay, error) {
-	r, err := c.request(&api.Request{
+	return c.startReplayContext(context.Background(), startReplay)
+}
+
+func (c *connection) startReplayContext(ctx context.Context, startReplay api.RequestStartReplay) (*api.ResponseStartReplay, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_StartReplay{
 			StartReplay: &startReplay,
 		},
@@ -42,7 +60,11 @@ func (c *connection) startReplay(startReplay api.RequestStartReplay) (*api.Respo
 }
 
 func (c *connection) leaveGame(leaveGame api.RequestLeaveGame) (*api.ResponseLeaveGame, error) {
-	r, err := c.request(&api.Request{
+	return c.leaveGameContext(context.Background(), leaveGame)
+}
+
+func (c *connection) leaveGameContext(ctx context.Context, leaveGame api.RequestLeaveGame) (*api.ResponseLeaveGame, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_LeaveGame{
 			LeaveGame: &leaveGame,
 		},
@@ -51,7 +73,11 @@ func (c *connection) leaveGame(leaveGame api.RequestLeaveGame) (*api.ResponseLea
 }
 
 func (c *connection) quickSave(quickSave api.RequestQuickSave) (*api.ResponseQuickSave, error) {
-	r, err := c.request(&api.Request{
+	return c.quickSaveContext(context.Background(), quickSave)
+}
+
+func (c *connection) quickSaveContext(ctx context.Context, quickSave api.RequestQuickSave) (*api.ResponseQuickSave, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_QuickSave{
 			QuickSave: &quickSave,
 		},
@@ -60,7 +86,11 @@ func (c *connection) quickSave(quickSave api.RequestQuickSave) (*api.ResponseQui
 }
 
 func (c *connection) quickLoad(quickLoad api.RequestQuickLoad) (*api.ResponseQuickLoad, error) {
-	r, err := c.request(&api.Request{
+	return c.quickLoadContext(context.Background(), quickLoad)
+}
+
+func (c *connection) quickLoadContext(ctx context.Context, quickLoad api.RequestQuickLoad) (*api.ResponseQuickLoad, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_QuickLoad{
 			QuickLoad: &quickLoad,
 		},
@@ -69,7 +99,11 @@ func (c *connection) quickLoad(quickLoad api.RequestQuickLoad) (*api.ResponseQui
 }
 
 func (c *connection) quit(quit api.RequestQuit) (*api.ResponseQuit, error) {
-	r, err := c.request(&api.Request{
+	return c.quitContext(context.Background(), quit)
+}
+
+func (c *connection) quitContext(ctx context.Context, quit api.RequestQuit) (*api.ResponseQuit, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_Quit{
 			Quit: &quit,
 		},
@@ -78,7 +112,11 @@ func (c *connection) quit(quit api.RequestQuit) (*api.ResponseQuit, error) {
 }
 
 func (c *connection) gameInfo(gameInfo api.RequestGameInfo) (*api.ResponseGameInfo, error) {
-	r, err := c.request(&api.Request{
+	return c.gameInfoContext(context.Background(), gameInfo)
+}
+
+func (c *connection) gameInfoContext(ctx context.Context, gameInfo api.RequestGameInfo) (*api.ResponseGameInfo, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_GameInfo{
 			GameInfo: &gameInfo,
 		},
@@ -87,7 +125,11 @@ func (c *connection) gameInfo(gameInfo api.RequestGameInfo) (*api.ResponseGameIn
 }
 
 func (c *connection) observation(observation api.RequestObservation) (*api.ResponseObservation, error) {
-	r, err := c.request(&api.Request{
+	return c.observationContext(context.Background(), observation)
+}
+
+func (c *connection) observationContext(ctx context.Context, observation api.RequestObservation) (*api.ResponseObservation, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_Observation{
 			Observation: &observation,
 		},
@@ -96,7 +138,11 @@ func (c *connection) observation(observation api.RequestObservation) (*api.Respo
 }
 
 func (c *connection) action(action api.RequestAction) (*api.ResponseAction, error) {
-	r, err := c.request(&api.Request{
+	return c.actionContext(context.Background(), action)
+}
+
+func (c *connection) actionContext(ctx context.Context, action api.RequestAction) (*api.ResponseAction, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_Action{
 			Action: &action,
 		},
@@ -105,7 +151,11 @@ func (c *connection) action(action api.RequestAction) (*api.ResponseAction, erro
 }
 
 func (c *connection) obsAction(obsAction api.RequestObserverAction) (*api.ResponseObserverAction, error) {
-	r, err := c.request(&api.Request{
+	return c.obsActionContext(context.Background(), obsAction)
+}
+
+func (c *connection) obsActionContext(ctx context.Context, obsAction api.RequestObserverAction) (*api.ResponseObserverAction, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_ObsAction{
 			ObsAction: &obsAction,
 		},
@@ -114,7 +164,11 @@ func (c *connection) obsAction(obsAction api.RequestObserverAction) (*api.Respon
 }
 
 func (c *connection) step(step api.RequestStep) (*api.ResponseStep, error) {
-	r, err := c.request(&api.Request{
+	return c.stepContext(context.Background(), step)
+}
+
+func (c *connection) stepContext(ctx context.Context, step api.RequestStep) (*api.ResponseStep, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_Step{
 			Step: &step,
 		},
@@ -123,7 +177,11 @@ func (c *connection) step(step api.RequestStep) (*api.ResponseStep, error) {
 }
 
 func (c *connection) data(data api.RequestData) (*api.ResponseData, error) {
-	r, err := c.request(&api.Request{
+	return c.dataContext(context.Background(), data)
+}
+
+func (c *connection) dataContext(ctx context.Context, data api.RequestData) (*api.ResponseData, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_Data{
 			Data: &data,
 		},
@@ -132,7 +190,11 @@ func (c *connection) data(data api.RequestData) (*api.ResponseData, error) {
 }
 
 func (c *connection) query(query api.RequestQuery) (*api.ResponseQuery, error) {
-	r, err := c.request(&api.Request{
+	return c.queryContext(context.Background(), query)
+}
+
+func (c *connection) queryContext(ctx context.Context, query api.RequestQuery) (*api.ResponseQuery, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_Query{
 			Query: &query,
 		},
@@ -141,7 +203,11 @@ func (c *connection) query(query api.RequestQuery) (*api.ResponseQuery, error) {
 }
 
 func (c *connection) saveReplay(saveReplay api.RequestSaveReplay) (*api.ResponseSaveReplay, error) {
-	r, err := c.request(&api.Request{
+	return c.saveReplayContext(context.Background(), saveReplay)
+}
+
+func (c *connection) saveReplayContext(ctx context.Context, saveReplay api.RequestSaveReplay) (*api.ResponseSaveReplay, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_SaveReplay{
 			SaveReplay: &saveReplay,
 		},
@@ -150,7 +216,11 @@ func (c *connection) saveReplay(saveReplay api.RequestSaveReplay) (*api.Response
 }
 
 func (c *connection) mapCommand(mapCommand api.RequestMapCommand) (*api.ResponseMapCommand, error) {
-	r, err := c.request(&api.Request{
+	return c.mapCommandContext(context.Background(), mapCommand)
+}
+
+func (c *connection) mapCommandContext(ctx context.Context, mapCommand api.RequestMapCommand) (*api.ResponseMapCommand, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_MapCommand{
 			MapCommand: &mapCommand,
 		},
@@ -159,7 +229,11 @@ func (c *connection) mapCommand(mapCommand api.RequestMapCommand) (*api.Response
 }
 
 func (c *connection) replayInfo(replayInfo api.RequestReplayInfo) (*api.ResponseReplayInfo, error) {
-	r, err := c.request(&api.Request{
+	return c.replayInfoContext(context.Background(), replayInfo)
+}
+
+func (c *connection) replayInfoContext(ctx context.Context, replayInfo api.RequestReplayInfo) (*api.ResponseReplayInfo, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_ReplayInfo{
 			ReplayInfo: &replayInfo,
 		},
@@ -168,7 +242,11 @@ func (c *connection) replayInfo(replayInfo api.RequestReplayInfo) (*api.Response
 }
 
 func (c *connection) availableMaps(availableMaps api.RequestAvailableMaps) (*api.ResponseAvailableMaps, error) {
-	r, err := c.request(&api.Request{
+	return c.availableMapsContext(context.Background(), availableMaps)
+}
+
+func (c *connection) availableMapsContext(ctx context.Context, availableMaps api.RequestAvailableMaps) (*api.ResponseAvailableMaps, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_AvailableMaps{
 			AvailableMaps: &availableMaps,
 		},
@@ -177,7 +255,11 @@ func (c *connection) availableMaps(availableMaps api.RequestAvailableMaps) (*api
 }
 
 func (c *connection) saveMap(saveMap api.RequestSaveMap) (*api.ResponseSaveMap, error) {
-	r, err := c.request(&api.Request{
+	return c.saveMapContext(context.Background(), saveMap)
+}
+
+func (c *connection) saveMapContext(ctx context.Context, saveMap api.RequestSaveMap) (*api.ResponseSaveMap, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_SaveMap{
 			SaveMap: &saveMap,
 		},
@@ -186,7 +268,11 @@ func (c *connection) saveMap(saveMap api.RequestSaveMap) (*api.ResponseSaveMap,
 }
 
 func (c *connection) ping(ping api.RequestPing) (*api.ResponsePing, error) {
-	r, err := c.request(&api.Request{
+	return c.pingContext(context.Background(), ping)
+}
+
+func (c *connection) pingContext(ctx context.Context, ping api.RequestPing) (*api.ResponsePing, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_Ping{
 			Ping: &ping,
 		},
@@ -195,7 +281,11 @@ func (c *connection) ping(ping api.RequestPing) (*api.ResponsePing, error) {
 }
 
 func (c *connection) debug(debug api.RequestDebug) (*api.ResponseDebug, error) {
-	r, err := c.request(&api.Request{
+	return c.debugContext(context.Background(), debug)
+}
+
+func (c *connection) debugContext(ctx context.Context, debug api.RequestDebug) (*api.ResponseDebug, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_Debug{
 			Debug: &debug,
 		},
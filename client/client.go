@@ -1,9 +1,9 @@
 package client
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/chippydip/go-sc2ai/api"
@@ -29,7 +29,17 @@ type Client struct {
 
 	debugDraw chan struct{}
 
+	reconnectPolicy ReconnectPolicy
+	disconnect      []func(error)
+	reconnected     []func()
+
+	errorsMu       sync.Mutex
+	clientErrors   []ClientError
+	protocolErrors []string
+	onError        []func(ClientError)
+
 	perfInterval uint32
+	metrics      []MetricsSink
 	lastDraw     []*api.DebugCommand
 
 	perfStart       time.Time
@@ -73,7 +83,7 @@ func (c *Client) Connect(address string, port int, timeout time.Duration) error
 		return fmt.Errorf("Unable to connect to game")
 	}
 
-	log.Printf("Connected to %v:%v", address, port)
+	c.Logger().Info("Connected", "address", address, "port", port)
 	return nil
 }
 
@@ -83,7 +93,7 @@ func (c *Client) TryConnect(address string, port int) error {
 		return err
 	}
 
-	log.Printf("Connected to %v:%v", address, port)
+	c.Logger().Info("Connected", "address", address, "port", port)
 	return nil
 }
 
@@ -91,7 +101,12 @@ func (c *Client) TryConnect(address string, port int) error {
 
 // CreateGame ...
 func (c *Client) CreateGame(mapPath string, players []*api.PlayerSetup, realtime bool) error {
-	r, err := c.connection.createGame(api.RequestCreateGame{
+	return c.CreateGameContext(context.Background(), mapPath, players, realtime)
+}
+
+// CreateGameContext is like CreateGame but gives up when ctx is done.
+func (c *Client) CreateGameContext(ctx context.Context, mapPath string, players []*api.PlayerSetup, realtime bool) error {
+	r, err := c.connection.createGameContext(ctx, api.RequestCreateGame{
 		Map: &api.RequestCreateGame_LocalMap{
 			LocalMap: &api.LocalMap{
 				MapPath: mapPath,
@@ -106,7 +121,7 @@ func (c *Client) CreateGame(mapPath string, players []*api.PlayerSetup, realtime
 	c.realtime = realtime
 
 	if r.Error != api.ResponseCreateGame_nil {
-		return fmt.Errorf("%v: %v", r.Error, r.GetErrorDetails())
+		return &CreateGameError{c.requestError("CreateGame", r.GetErrorDetails()), r.Error}
 	}
 
 	return nil
@@ -114,6 +129,11 @@ func (c *Client) CreateGame(mapPath string, players []*api.PlayerSetup, realtime
 
 // RequestJoinGame ...
 func (c *Client) RequestJoinGame(setup *api.PlayerSetup, options *api.InterfaceOptions, ports Ports) error {
+	return c.RequestJoinGameContext(context.Background(), setup, options, ports)
+}
+
+// RequestJoinGameContext is like RequestJoinGame but gives up when ctx is done.
+func (c *Client) RequestJoinGameContext(ctx context.Context, setup *api.PlayerSetup, options *api.InterfaceOptions, ports Ports) error {
 	req := api.RequestJoinGame{
 		Participation: &api.RequestJoinGame_Race{
 			Race: setup.Race,
@@ -125,13 +145,13 @@ func (c *Client) RequestJoinGame(setup *api.PlayerSetup, options *api.InterfaceO
 		req.ServerPorts = ports.ServerPorts
 		req.ClientPorts = ports.ClientPorts
 	}
-	r, err := c.connection.joinGame(req)
+	r, err := c.connection.joinGameContext(ctx, req)
 	if err != nil {
 		return err
 	}
 
 	if r.Error != api.ResponseJoinGame_nil {
-		return fmt.Errorf("%v: %v", r.Error.String(), r.GetErrorDetails())
+		return &JoinGameError{c.requestError("JoinGame", r.GetErrorDetails()), r.Error}
 	}
 
 	c.playerID = r.GetPlayerId()
@@ -150,7 +170,7 @@ func (c *Client) RequestReplayInfo(path string) (*api.ResponseReplayInfo, error)
 		return nil, err
 	}
 	if r.Error != api.ResponseReplayInfo_nil {
-		return nil, fmt.Errorf("%v: %v", r.Error.String(), r.GetErrorDetails())
+		return nil, &ReplayInfoError{c.requestError("ReplayInfo", r.GetErrorDetails()), r.Error}
 	}
 	return r, nil
 }
@@ -169,43 +189,133 @@ func (c *Client) RequestStartReplay(request api.RequestStartReplay) error {
 		return err
 	}
 	if r.Error != api.ResponseStartReplay_nil {
-		return fmt.Errorf("%v: %v", r.Error.String(), r.GetErrorDetails())
+		return &StartReplayError{c.requestError("StartReplay", r.GetErrorDetails()), r.Error}
 	}
 
 	c.replayInfo, err = c.RequestReplayInfo(request.GetReplayPath())
 	if err != nil {
-		log.Print(err)
+		c.recordError("ReplayInfo", err)
 	}
 	return nil
 }
 
 // RequestLeaveGame ...
 func (c *Client) RequestLeaveGame() error {
-	_, err := c.connection.leaveGame(api.RequestLeaveGame{})
+	return c.RequestLeaveGameContext(context.Background())
+}
+
+// RequestLeaveGameContext is like RequestLeaveGame but gives up when ctx is done.
+func (c *Client) RequestLeaveGameContext(ctx context.Context) error {
+	_, err := c.connection.leaveGameContext(ctx, api.RequestLeaveGame{})
 	return err
 }
 
+// QuickSave saves the current game state so it can be restored later with QuickLoad.
+func (c *Client) QuickSave() error {
+	return c.QuickSaveContext(context.Background())
+}
+
+// QuickSaveContext is like QuickSave but gives up when ctx is done.
+func (c *Client) QuickSaveContext(ctx context.Context) error {
+	_, err := c.connection.quickSaveContext(ctx, api.RequestQuickSave{})
+	return err
+}
+
+// QuickLoad restores the game state from the last QuickSave and re-initializes cached state.
+func (c *Client) QuickLoad() error {
+	return c.QuickLoadContext(context.Background())
+}
+
+// QuickLoadContext is like QuickLoad but gives up when ctx is done.
+func (c *Client) QuickLoadContext(ctx context.Context) error {
+	if _, err := c.connection.quickLoadContext(ctx, api.RequestQuickLoad{}); err != nil {
+		return err
+	}
+	return c.reset(ctx)
+}
+
+// RestartGame restarts a single player game and re-initializes cached state.
+func (c *Client) RestartGame() error {
+	return c.RestartGameContext(context.Background())
+}
+
+// RestartGameContext is like RestartGame but gives up when ctx is done.
+func (c *Client) RestartGameContext(ctx context.Context) error {
+	r, err := c.connection.restartGameContext(ctx, api.RequestRestartGame{})
+	if err != nil {
+		return err
+	}
+	if r.Error != api.ResponseRestartGame_nil {
+		return &RestartGameError{c.requestError("RestartGame", r.GetErrorDetails()), r.Error, r.GetNeedHardReset()}
+	}
+	return c.reset(ctx)
+}
+
+// reset re-initializes cached state after the game state was replaced by a load or restart.
+func (c *Client) reset(ctx context.Context) error {
+	// Draw commands from before may not make sense anymore
+	c.ClearDebugDraw()
+
+	observation, err := c.connection.observationContext(ctx, api.RequestObservation{})
+	if err != nil {
+		return err
+	}
+	c.observation = observation
+
+	c.upgrades = map[api.UpgradeID]struct{}{}
+	c.newUpgrades = nil
+	for _, upgrade := range c.observation.GetObservation().GetRawData().GetPlayer().GetUpgradeIds() {
+		c.upgrades[upgrade] = struct{}{}
+	}
+
+	// Unit data is upgrade-dependent
+	data, err := c.connection.dataContext(ctx, api.RequestData{
+		UnitTypeId: true,
+	})
+	if err != nil {
+		return err
+	}
+	if c.data != nil {
+		c.data.Units = data.GetUnits()
+	}
+
+	c.resetPerf(time.Now(), c.observation.GetObservation().GetGameLoop())
+	return nil
+}
+
 // Init ...
 func (c *Client) Init() error {
+	return c.InitContext(context.Background())
+}
+
+// InitContext is like Init but gives up when ctx is done.
+func (c *Client) InitContext(ctx context.Context) error {
 	var infoErr, dataErr, obsErr error
 
 	// Fire off all three requests
-	c.gameInfo, infoErr = c.connection.gameInfo(api.RequestGameInfo{})
-	c.data, dataErr = c.connection.data(api.RequestData{
+	var gameInfo *api.ResponseGameInfo
+	gameInfo, infoErr = c.connection.gameInfoContext(ctx, api.RequestGameInfo{})
+	c.data, dataErr = c.connection.dataContext(ctx, api.RequestData{
 		AbilityId:  true,
 		UnitTypeId: true,
 		UpgradeId:  true,
 		BuffId:     true,
 		EffectId:   true,
 	})
-	c.observation, obsErr = c.connection.observation(api.RequestObservation{})
+	c.observation, obsErr = c.connection.observationContext(ctx, api.RequestObservation{})
 	c.upgrades = map[api.UpgradeID]struct{}{}
 
-	c.perfStart = time.Now()
-	c.perfStartFrame = c.observation.GetObservation().GetGameLoop()
+	c.resetPerf(time.Now(), c.observation.GetObservation().GetGameLoop())
+	c.setGameInfo(gameInfo)
+
+	return firstOrNil(infoErr, dataErr, obsErr)
+}
+
+func (c *Client) setGameInfo(gameInfo *api.ResponseGameInfo) {
+	c.gameInfo = gameInfo
 
 	// This info isn't provided for replays, so try to normalize things
-	if c.replayInfo != nil {
+	if c.replayInfo != nil && c.gameInfo != nil {
 		c.gameInfo.MapName = c.replayInfo.MapName
 		c.gameInfo.LocalMapPath = c.replayInfo.LocalMapPath
 		c.gameInfo.PlayerInfo = make([]*api.PlayerInfo, len(c.replayInfo.PlayerInfo))
@@ -213,12 +323,16 @@ func (c *Client) Init() error {
 			c.gameInfo.PlayerInfo[i] = pie.PlayerInfo
 		}
 	}
-
-	return firstOrNil(infoErr, dataErr, obsErr)
 }
 
 // Step ...
 func (c *Client) Step(stepSize int) error {
+	return c.StepContext(context.Background(), stepSize)
+}
+
+// StepContext is like Step but gives up when ctx is done. Callbacks are still run, only the
+// requests to the game are bounded by ctx.
+func (c *Client) StepContext(ctx context.Context, stepSize int) error {
 	var err error
 
 	// Call before callbacks
@@ -230,10 +344,9 @@ func (c *Client) Step(stepSize int) error {
 
 	// Step the simulation forward if this isn't in realtime mode
 	t = time.Now()
+	step := c.observation.GetObservation().GetGameLoop() + uint32(stepSize)
 	if !c.realtime && stepSize > 0 {
-		if _, err := c.connection.step(api.RequestStep{
-			Count: uint32(stepSize),
-		}); err != nil {
+		if err := c.stepTo(ctx, step); err != nil {
 			return err
 		}
 	}
@@ -241,11 +354,15 @@ func (c *Client) Step(stepSize int) error {
 
 	// Get an updated observation
 	t = time.Now()
-	step := c.observation.GetObservation().GetGameLoop() + uint32(stepSize)
 	for {
-		if c.observation, err = c.connection.observation(api.RequestObservation{GameLoop: step}); err != nil {
-			return err
+		var obs *api.ResponseObservation
+		if obs, err = c.connection.observationContext(ctx, api.RequestObservation{GameLoop: step}); err != nil {
+			if !c.recovered(ctx, err) {
+				return err
+			}
+			obs, err = c.observation, nil // re-synced during the reconnect
 		}
+		c.observation = obs
 
 		actionsCompleted := len(c.observation.GetActions())
 		c.actionsCompleted += actionsCompleted
@@ -272,7 +389,7 @@ func (c *Client) Step(stepSize int) error {
 
 	// Check for new upgrades
 	c.newUpgrades = nil
-	for _, upgrade := range c.observation.GetObservation().GetRawData().GetPlayer().UpgradeIds {
+	for _, upgrade := range c.observation.GetObservation().GetRawData().GetPlayer().GetUpgradeIds() {
 		if _, ok := c.upgrades[upgrade]; !ok {
 			c.newUpgrades = append(c.newUpgrades, upgrade)
 			c.upgrades[upgrade] = struct{}{}
@@ -283,7 +400,7 @@ func (c *Client) Step(stepSize int) error {
 		// Re-fetch unit data since some of it is upgrade-dependent
 		// TODO: also (re-)fetch unit -> ability mapping?
 		var data *api.ResponseData
-		data, err = c.connection.data(api.RequestData{
+		data, err = c.connection.dataContext(ctx, api.RequestData{
 			UnitTypeId: true,
 		})
 		c.data.Units = data.GetUnits()
@@ -303,27 +420,62 @@ func (c *Client) Step(stepSize int) error {
 	return err
 }
 
+// stepTo steps the game until gameLoop, re-sending the remainder if the connection drops.
+func (c *Client) stepTo(ctx context.Context, gameLoop uint32) error {
+	count := gameLoop - c.observation.GetObservation().GetGameLoop()
+	for {
+		_, err := c.connection.stepContext(ctx, api.RequestStep{
+			Count: count,
+		})
+		if err == nil {
+			return nil
+		}
+		if !c.recovered(ctx, err) {
+			return err
+		}
+
+		// The step may or may not have been applied before the drop
+		current := c.observation.GetObservation().GetGameLoop()
+		if current >= gameLoop || !c.IsInGame() {
+			return nil
+		}
+		count = gameLoop - current
+	}
+}
+
 func (c *Client) reportPerf() {
 	perfStart, perfStartFrame := time.Now(), c.observation.GetObservation().GetGameLoop()
-	total, frames := perfStart.Sub(c.perfStart), time.Duration(perfStartFrame-c.perfStartFrame)
-
-	text := "" +
-		fmt.Sprintf("frames:      %v\n", int(frames)) +
-		fmt.Sprintf("frameTime:   %v\n", total/frames) +
-		"\n" +
-		fmt.Sprintf("beforeStep:  %v\n", c.beforeStepTime/frames) +
-		fmt.Sprintf("step:        %v\n", c.stepTime/frames) +
-		fmt.Sprintf("observation: %v\n", c.observationTime/frames) +
-		fmt.Sprintf("afterStep:   %v\n", c.afterStepTime/frames) +
-		"\n" +
-		fmt.Sprintf("actions:     %v/%v\n", c.actionsCompleted, c.actions) +
-		fmt.Sprintf("maxActions:  %v\n", c.maxActions) +
-		fmt.Sprintf("obsActions:  %v\n", c.observerActions) +
-		fmt.Sprintf("debugCmds:   %v\n", c.debugCommands) +
-		""
-	text = strings.Replace(text, "µ", "u", -1)
-
-	// Reset perf counters
+
+	stats := PerfStats{
+		PlayerID:         c.playerID,
+		GameLoop:         perfStartFrame,
+		Elapsed:          perfStart.Sub(c.perfStart),
+		BeforeStep:       c.beforeStepTime,
+		Step:             c.stepTime,
+		Observation:      c.observationTime,
+		AfterStep:        c.afterStepTime,
+		Actions:          c.actions,
+		MaxActions:       c.maxActions,
+		ActionsCompleted: c.actionsCompleted,
+		ObserverActions:  c.observerActions,
+		DebugCommands:    c.debugCommands,
+	}
+	if perfStartFrame > c.perfStartFrame {
+		stats.Frames = perfStartFrame - c.perfStartFrame
+	}
+
+	c.resetPerf(perfStart, perfStartFrame)
+
+	if len(c.metrics) == 0 {
+		c.drawPerf(stats)
+	}
+	for _, sink := range c.metrics {
+		sink.ReportPerf(stats)
+	}
+}
+
+// resetPerf resets the perf counters to start counting from the given time and game loop.
+func (c *Client) resetPerf(perfStart time.Time, perfStartFrame uint32) {
 	c.perfStart = perfStart
 	c.perfStartFrame = perfStartFrame
 	c.beforeStepTime = 0
@@ -335,21 +487,6 @@ func (c *Client) reportPerf() {
 	c.actionsCompleted = 0
 	c.observerActions = 0
 	c.debugCommands = 0
-
-	c.SendDebugCommands(append(c.lastDraw, &api.DebugCommand{
-		Command: &api.DebugCommand_Draw{
-			Draw: &api.DebugDraw{
-				Text: []*api.DebugText{
-					&api.DebugText{
-						Color:      &api.Color{R: 255, G: 255, B: 255},
-						Text:       text,
-						VirtualPos: &api.Point{X: 0, Y: 0, Z: 0},
-					},
-				},
-			},
-		},
-	}))
-	c.lastDraw = c.lastDraw[:len(c.lastDraw)-1]
 }
 
 // SaveReplay(path string) error
@@ -380,7 +517,12 @@ func (c *Client) IsInGame() bool {
 
 // GetObservation ...
 func (c *Client) GetObservation() (*api.ResponseObservation, error) {
-	return c.connection.observation(api.RequestObservation{})
+	return c.GetObservationContext(context.Background())
+}
+
+// GetObservationContext is like GetObservation but gives up when ctx is done.
+func (c *Client) GetObservationContext(ctx context.Context) (*api.ResponseObservation, error) {
+	return c.connection.observationContext(ctx, api.RequestObservation{})
 }
 
 // PollResponse() bool
@@ -392,26 +534,16 @@ func (c *Client) GetObservation() (*api.ResponseObservation, error) {
 // // Diagnostic
 // DumpProtoUsage()
 
-// Error(err ClientError, errors []string)
-// ErrorIf(condition bool, err ClientError, errors []string)
-
-// func (c *Client) GetClientErrors() []ClientError {
-// 	return nil
-// }
-
-// func (c *Client) GetProtocolErrors() []string {
-// 	return nil
-// }
-
-// ClearClientErrors()
-// ClearProtocolErrors()
-
 // UseGeneralizedAbility(value bool)
 
 // // Save/Load
 // Save()
 // Load()
 
+func (c *Client) requestError(request, details string) RequestError {
+	return RequestError{Request: request, Status: c.connection.Status, Details: details}
+}
+
 func firstOrNil(errs ...error) error {
 	for _, e := range errs {
 		if e != nil {
@@ -1,10 +1,11 @@
 package client
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"reflect"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -20,9 +21,19 @@ type connection struct {
 
 	counter  uint32
 	requests chan<- request
+	dropped  <-chan struct{}
+	dropErr  error
+
+	address   string
+	port      int
+	reconnect func(ctx context.Context) error
+	recorder  *Recorder
+	logger    *slog.Logger
 }
 
 type request struct {
+	name     string
+	id       uint32
 	data     []byte
 	response chan<- response
 }
@@ -34,9 +45,9 @@ type response struct {
 
 // MaxMessageSize is the largest protobuf message that can be sent without getting disconnected.
 // The gorilla/websocket implementation fragments messages above it's write buffer size and the
-// SC2 game doesn't seem to be able to deal with these messages. There is not a check in place
-// to prevent large messages from being sent and warnings will be printed if a message size
-// exceeds half of this limit. The default is now 2MB (up from 4kb) but can be overrided by
+// SC2 game doesn't seem to be able to deal with these messages. Larger messages are rejected
+// (SendActions and SendDebugCommands split their batches to stay below it) and warnings will be
+// printed if a message size exceeds half of this limit. The default is now 2MB (up from 4kb) but can be overrided by
 // modifying this value before connecting to SC2.
 var MaxMessageSize = 2 * 1024 * 1024
 
@@ -51,20 +62,33 @@ func (c *connection) Connect(address string, port int) error {
 	if err != nil {
 		return err
 	}
+	c.address, c.port = address, port
+
+	return c.connectTransport(websocketTransport{ws})
+}
+
+// connectTransport starts a worker to process requests over t and pings the game.
+func (c *connection) connectTransport(t Transport) error {
+	c.Status = api.Status_unknown
 
 	requests := make(chan request)
-	ws.SetCloseHandler(func(code int, text string) error {
-		close(requests)
-		return nil
-	})
+	dropped := make(chan struct{})
 	c.requests = requests
+	c.dropped = dropped
+	recorder := c.recorder
 
 	// Worker
 	go func() {
 		defer recoverPanic()
+		defer t.Close()
 
 		for r := range requests {
-			r.process(ws)
+			if err := r.process(t, recorder); err != nil {
+				// Any transport error leaves the connection unusable
+				c.dropErr = err
+				close(dropped)
+				return
+			}
 		}
 	}()
 
@@ -76,31 +100,96 @@ func (c *connection) Connect(address string, port int) error {
 	return nil
 }
 
-func (r request) process(ws *websocket.Conn) {
-	data, err := []byte(nil), ws.WriteMessage(websocket.BinaryMessage, r.data)
-	if err == nil {
-		_, data, err = ws.ReadMessage()
+// dropCause waits for the worker to report a drop and returns the websocket error.
+func (c *connection) dropCause() error {
+	<-c.dropped
+	return c.dropErr
+}
+
+// isDropped returns true if the websocket was lost since the last successful Connect.
+func (c *connection) isDropped() bool {
+	select {
+	case <-c.dropped:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r request) process(t Transport, recorder *Recorder) error {
+	start := time.Now()
+	data, err := t.RoundTrip(r.data)
+	if recorder != nil {
+		recorder.record(r, start, data, err)
 	}
 
-	r.response <- response{data, err}
+	if err != nil {
+		r.response <- response{nil, &DisconnectedError{Err: err}}
+	} else {
+		r.response <- response{data, nil}
+	}
 	close(r.response)
+	return err
 }
 
-func (c *connection) sendRecv(data []byte, name string) ([]byte, error) {
-	out := make(chan response)
-	c.requests <- request{data, out}
+// sendRecv queues the request and waits for the response or for ctx to be done. Requests are
+// processed strictly in order, so if ctx expires after the request was sent the worker still
+// reads (and discards) its response before starting the next request. This keeps later
+// requests paired with the correct responses.
+func (c *connection) sendRecv(ctx context.Context, data []byte, name string, id uint32) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, &TimeoutError{Request: name, Err: err}
+	}
+
+	out := make(chan response, 1) // buffered so an abandoned request never blocks the worker
+	select {
+	case c.requests <- request{name, id, data, out}:
+	case <-c.dropped:
+		return nil, &DisconnectedError{Request: name, Err: c.dropCause()}
+	case <-ctx.Done():
+		return nil, &TimeoutError{Request: name, Err: ctx.Err()}
+	}
 
 	for {
 		select {
 		case r := <-out:
+			if e, ok := r.error.(*DisconnectedError); ok {
+				e.Request = name
+			}
 			return r.data, r.error
+		case <-ctx.Done():
+			return nil, &TimeoutError{Request: name, Sent: true, Err: ctx.Err()}
 		case <-time.After(10 * time.Second):
-			log.Printf("waiting for %v response", name)
+			c.log().Warn("Waiting for response", "request", name)
 		}
 	}
 }
 
-func (c *connection) request(r *api.Request) (*api.Response, error) {
+// log returns the client's logger or the default logger if it doesn't have one.
+func (c *connection) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return slog.Default()
+}
+
+// requestName returns the short name of the request type (e.g. "Step").
+func requestName(r *api.Request) string {
+	return strings.TrimPrefix(reflect.TypeOf(r.Request).String(), "*api.Request_")
+}
+
+func (c *connection) request(ctx context.Context, r *api.Request) (*api.Response, error) {
+	if c.requests == nil {
+		return nil, &DisconnectedError{Request: requestName(r), Err: errNotConnected}
+	}
+
+	// Try to re-establish a dropped connection before sending anything new
+	if c.reconnect != nil && c.isDropped() {
+		if err := c.reconnect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	r.Id = atomic.AddUint32(&c.counter, 1)
 
 	// Serialize
@@ -111,14 +200,14 @@ func (c *connection) request(r *api.Request) (*api.Response, error) {
 
 	if len(data) > MaxMessageSize {
 		err = fmt.Errorf("message too large: %v (max %v)", len(data), MaxMessageSize)
-		log.Print(err)
+		c.log().Error("Message too large", "request", requestName(r), "size", len(data), "max", MaxMessageSize)
 		return nil, err
 	} else if len(data) > MaxMessageSize/2 {
-		log.Print("warning, large message size: ", len(data))
+		c.log().Warn("Large message size", "request", requestName(r), "size", len(data))
 	}
 
 	// Send/Recv
-	data, err = c.sendRecv(data, reflect.TypeOf(r.Request).String())
+	data, err = c.sendRecv(ctx, data, requestName(r), r.Id)
 	if err != nil {
 		return nil, err
 	}
@@ -137,16 +226,15 @@ func (c *connection) request(r *api.Request) (*api.Response, error) {
 
 	// Check Id
 	if resp.Id != 0 && resp.Id != r.Id {
-		log.Printf("bad response ID: got %v, expected %v", resp.Id, r.Id)
+		c.log().Warn("Bad response ID", "got", resp.Id, "expected", r.Id)
 	}
 
 	// Report errors (if any) and return
-	switch len(resp.Error) {
-	case 0:
-		return resp, nil
-	case 1:
-		return nil, errors.New(resp.Error[0])
-	default:
-		return nil, fmt.Errorf("%v", resp.Error)
+	if len(resp.Error) > 0 {
+		return nil, &ResponseError{
+			RequestError: RequestError{Request: requestName(r), Status: c.Status},
+			Errors:       resp.Error,
+		}
 	}
+	return resp, nil
 }
@@ -0,0 +1,360 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/sc2test"
+)
+
+func TestStepContextTimeout(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	// Hang the first step long enough for the caller to give up
+	hung := false
+	s.Handle = func(r *api.Request) *api.Response {
+		if r.GetStep() != nil && !hung {
+			hung = true
+			time.Sleep(200 * time.Millisecond)
+		}
+		return nil
+	}
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := c.StepContext(ctx, 1)
+	var timeout *client.TimeoutError
+	if !errors.As(err, &timeout) || !timeout.Sent || !timeout.Timeout() || timeout.Request != "Step" {
+		t.Fatalf("expected a sent Step timeout, got %#v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error to wrap context.DeadlineExceeded: %v", err)
+	}
+
+	// The late step response must be discarded and not confused with the next one
+	obs, err := c.GetObservation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obs.GetObservation().GetGameLoop() != 1 {
+		t.Errorf("expected game loop 1 after the abandoned step, got %v", obs.GetObservation().GetGameLoop())
+	}
+}
+
+func TestQueryContextCanceled(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.QueryContext(ctx, api.RequestQuery{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSplitLargeBatches(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	// Fail every other action so misaligned results would be noticed
+	s.Action = func(r *api.RequestAction) *api.ResponseAction {
+		resp := &api.ResponseAction{}
+		for _, a := range r.GetActions() {
+			if a.GetActionChat().GetMessage()[0]%2 == 0 {
+				resp.Result = append(resp.Result, api.ActionResult_Success)
+			} else {
+				resp.Result = append(resp.Result, api.ActionResult_Error)
+			}
+		}
+		return resp
+	}
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(size int) { client.MaxMessageSize = size }(client.MaxMessageSize)
+	client.MaxMessageSize = 1024
+
+	var actions []*api.Action
+	for i := 0; i < 100; i++ {
+		actions = append(actions, &api.Action{ActionChat: &api.ActionChat{
+			Message: string(rune('0'+i%10)) + " padding to make the batch larger than one message",
+		}})
+	}
+	results, err := c.SendActionsContext(context.Background(), actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(actions) {
+		t.Fatalf("got %v results for %v actions", len(results), len(actions))
+	}
+	for i, r := range results {
+		if expected := []api.ActionResult{api.ActionResult_Success, api.ActionResult_Error}[i%2]; r != expected {
+			t.Errorf("action %v: got %v, expected %v", i, r, expected)
+		}
+	}
+
+	var commands []*api.DebugCommand
+	for i := 0; i < 100; i++ {
+		commands = append(commands, &api.DebugCommand{Command: &api.DebugCommand_KillUnit{KillUnit: &api.DebugKillUnit{
+			Tag: []api.UnitTag{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+		}}})
+	}
+	if err := c.SendDebugCommandsContext(context.Background(), commands); err != nil {
+		t.Fatal(err)
+	}
+
+	var actionRequests, debugRequests, debugCommands int
+	for _, r := range s.Requests() {
+		if a := r.GetAction(); a != nil {
+			actionRequests++
+		}
+		if d := r.GetDebug(); d != nil {
+			debugRequests++
+			debugCommands += len(d.GetDebug())
+		}
+	}
+	if actionRequests < 2 || debugRequests < 2 || debugCommands != len(commands) {
+		t.Errorf("action requests: %v, debug requests: %v, debug commands: %v", actionRequests, debugRequests, debugCommands)
+	}
+
+	// A failed request still returns a result for every action
+	requests := 0
+	s.Handle = func(r *api.Request) *api.Response {
+		if r.GetAction() != nil {
+			if requests++; requests == 2 {
+				return &api.Response{Error: []string{"failed"}}
+			}
+		}
+		return nil
+	}
+	results, err = c.SendActionsContext(context.Background(), actions)
+	if err == nil {
+		t.Fatal("expected the second batch to fail")
+	}
+	if len(results) != len(actions) {
+		t.Fatalf("got %v results for %v actions", len(results), len(actions))
+	}
+	first := len(s.Requests()[len(s.Requests())-2].GetAction().GetActions())
+	for i, r := range results {
+		expected := api.ActionResult_Error
+		if i < first && i%2 == 0 {
+			expected = api.ActionResult_Success
+		}
+		if r != expected {
+			t.Errorf("action %v: got %v, expected %v", i, r, expected)
+		}
+	}
+}
+
+func TestDebugDrawInOneRequest(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(size int) { client.MaxMessageSize = size }(client.MaxMessageSize)
+	client.MaxMessageSize = 1024
+
+	// A later draw request would replace the earlier ones, so everything must be drawn at once
+	draw := &api.DebugDraw{}
+	for i := 0; i < 50; i++ {
+		draw.Text = append(draw.Text, &api.DebugText{Text: "text"})
+		draw.Lines = append(draw.Lines, &api.DebugLine{Line: &api.Line{P0: &api.Point{X: 1}, P1: &api.Point{Y: 1}}})
+		draw.Spheres = append(draw.Spheres, &api.DebugSphere{P: &api.Point{X: 1}, R: 1})
+	}
+	commands := []*api.DebugCommand{
+		{Command: &api.DebugCommand_Draw{Draw: draw}},
+		{Command: &api.DebugCommand_KillUnit{KillUnit: &api.DebugKillUnit{Tag: []api.UnitTag{1}}}},
+		{Command: &api.DebugCommand_Draw{Draw: &api.DebugDraw{Text: []*api.DebugText{{Text: "more"}}}}},
+	}
+	if err := c.SendDebugCommandsContext(context.Background(), commands); err != nil {
+		t.Fatal(err)
+	}
+
+	var draws []*api.DebugDraw
+	var kills int
+	for _, r := range s.Requests() {
+		for _, cmd := range r.GetDebug().GetDebug() {
+			if d := cmd.GetDraw(); d != nil {
+				draws = append(draws, d)
+			}
+			if cmd.GetKillUnit() != nil {
+				kills++
+			}
+		}
+	}
+	if len(draws) != 1 || kills != 1 {
+		t.Fatalf("got %v draw commands and %v kill commands, expected 1 of each", len(draws), kills)
+	}
+	d := draws[0]
+	if len(d.Text) != 51 || len(d.Lines) == 0 || len(d.Lines) == 50 || len(d.Spheres) != 0 {
+		t.Errorf("got %v text, %v lines, %v spheres", len(d.Text), len(d.Lines), len(d.Spheres))
+	}
+	if size := (&api.Request{Request: &api.Request_Debug{Debug: &api.RequestDebug{Debug: []*api.DebugCommand{{Command: &api.DebugCommand_Draw{Draw: d}}}}}}).Size(); size > client.MaxMessageSize {
+		t.Errorf("got a %v byte draw request", size)
+	}
+}
+
+func TestPerfTextKeepsDraws(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	c.SetPerfInterval(2)
+
+	c.SendDebugCommands([]*api.DebugCommand{
+		{Command: &api.DebugCommand_Draw{Draw: &api.DebugDraw{Text: []*api.DebugText{{Text: "bot"}}}}},
+	})
+	for i := 0; i < 4; i++ { // two perf intervals
+		if err := c.Step(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var last *api.DebugDraw
+	perfDraws := 0
+	for _, r := range s.Requests() {
+		for _, cmd := range r.GetDebug().GetDebug() {
+			if d := cmd.GetDraw(); d != nil {
+				last = d
+				if len(d.Text) > 0 && strings.HasPrefix(d.Text[0].Text, "frames:") {
+					perfDraws++
+				}
+			}
+		}
+	}
+	if perfDraws != 2 {
+		t.Fatalf("got %v perf draws, expected 2", perfDraws)
+	}
+	if len(last.Text) != 2 || last.Text[1].Text != "bot" {
+		t.Errorf("expected the bot's draw to be redrawn with the perf text, got %v", last.Text)
+	}
+}
+
+func TestQuickLoadAndRestart(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Step(10); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.QuickSave(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.Step(10); err != nil {
+			t.Fatal(err)
+		}
+		if loop := c.Observation().GetObservation().GetGameLoop(); loop != 20 {
+			t.Errorf("expected loop 20 before loading, got %v", loop)
+		}
+		if err := c.QuickLoad(); err != nil {
+			t.Fatal(err)
+		}
+		if loop := c.Observation().GetObservation().GetGameLoop(); loop != 10 {
+			t.Errorf("expected loop 10 after loading, got %v", loop)
+		}
+	}
+
+	if err := c.RestartGame(); err != nil {
+		t.Fatal(err)
+	}
+	if loop := c.Observation().GetObservation().GetGameLoop(); loop != 0 {
+		t.Errorf("expected loop 0 after restarting, got %v", loop)
+	}
+	if err := c.QuickLoad(); err == nil {
+		t.Error("expected an error loading after a restart")
+	}
+}
+
+func TestLoggerAddsGameState(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.GameInfo = &api.ResponseGameInfo{
+		MapName: "Test LE",
+		PlayerInfo: []*api.PlayerInfo{
+			{PlayerId: 1, Type: api.PlayerType_Participant, RaceRequested: api.Race_Protoss},
+			{PlayerId: 2, Type: api.PlayerType_Computer, RaceRequested: api.Race_Zerg, Difficulty: api.Difficulty_Hard},
+		},
+	}
+
+	var buf bytes.Buffer
+	c := &client.Client{}
+	c.SetLogOutput(&buf)
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	players := []*api.PlayerSetup{
+		{Type: api.PlayerType_Participant, Race: api.Race_Protoss},
+		{Type: api.PlayerType_Computer, Race: api.Race_Zerg, Difficulty: api.Difficulty_Hard},
+	}
+	if err := c.CreateGame("test", players, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RequestJoinGame(players[0], &api.InterfaceOptions{Raw: true}, client.Ports{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Step(8); err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+	c.Logger().Info("hello")
+	line := buf.String()
+	for _, attr := range []string{"msg=hello", "player=1", "loop=8", `map="Test LE"`, "opponent=Zerg(Hard)"} {
+		if !strings.Contains(line, attr) {
+			t.Errorf("missing %v in %q", attr, line)
+		}
+	}
+}
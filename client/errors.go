@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// ErrDisconnected matches (via errors.Is) any error caused by losing the connection to the game.
+var ErrDisconnected = errors.New("disconnected from game")
+
+var errNotConnected = errors.New("not connected")
+
+// DisconnectedError is returned for requests that were pending or issued after the websocket
+// to the game was lost.
+type DisconnectedError struct {
+	Request string // short request type name, e.g. "Step"
+	Err     error  // the underlying websocket error
+}
+
+func (e *DisconnectedError) Error() string {
+	return fmt.Sprintf("%v failed, %v: %v", e.Request, ErrDisconnected, e.Err)
+}
+
+// Unwrap returns the underlying websocket error.
+func (e *DisconnectedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrDisconnected.
+func (e *DisconnectedError) Is(target error) bool {
+	return target == ErrDisconnected
+}
+
+// TimeoutError is returned when a request is abandoned because its context expired or was
+// canceled. If Sent is true the game already received the request and may still act on it;
+// its response will be discarded when it arrives.
+type TimeoutError struct {
+	Request string // short request type name, e.g. "Step"
+	Sent    bool
+	Err     error // the context error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Sent {
+		return fmt.Sprintf("%v response not received: %v", e.Request, e.Err)
+	}
+	return fmt.Sprintf("%v request not sent: %v", e.Request, e.Err)
+}
+
+// Unwrap returns the underlying context error so errors.Is(err, context.DeadlineExceeded) works.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout returns true if the request hit a deadline (rather than being canceled).
+func (e *TimeoutError) Timeout() bool {
+	return e.Err == context.DeadlineExceeded
+}
+
+// RequestError holds the context common to all errors reported by the game.
+type RequestError struct {
+	Request string     // short request type name, e.g. "CreateGame"
+	Status  api.Status // game status after the failed request
+	Details string
+}
+
+func (e RequestError) format(code fmt.Stringer) string {
+	if len(e.Details) == 0 {
+		return fmt.Sprintf("%v: %v", e.Request, code)
+	}
+	return fmt.Sprintf("%v: %v: %v", e.Request, code, e.Details)
+}
+
+// ResponseError is returned when the game reports generic errors for a request (Response.Error).
+type ResponseError struct {
+	RequestError
+	Errors []string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%v request failed (status %v): %v", e.Request, e.Status, strings.Join(e.Errors, "; "))
+}
+
+// CreateGameError is returned when a create_game request fails. It matches another
+// *CreateGameError with the same Code when using errors.Is.
+type CreateGameError struct {
+	RequestError
+	Code api.ResponseCreateGame_Error
+}
+
+func (e *CreateGameError) Error() string { return e.format(e.Code) }
+
+// Is reports whether target is a *CreateGameError with the same Code.
+func (e *CreateGameError) Is(target error) bool {
+	t, ok := target.(*CreateGameError)
+	return ok && t.Code == e.Code
+}
+
+// JoinGameError is returned when a join_game request fails. It matches another
+// *JoinGameError with the same Code when using errors.Is.
+type JoinGameError struct {
+	RequestError
+	Code api.ResponseJoinGame_Error
+}
+
+func (e *JoinGameError) Error() string { return e.format(e.Code) }
+
+// Is reports whether target is a *JoinGameError with the same Code.
+func (e *JoinGameError) Is(target error) bool {
+	t, ok := target.(*JoinGameError)
+	return ok && t.Code == e.Code
+}
+
+// RestartGameError is returned when a restart_game request fails. It matches another
+// *RestartGameError with the same Code when using errors.Is.
+type RestartGameError struct {
+	RequestError
+	Code          api.ResponseRestartGame_Error
+	NeedHardReset bool
+}
+
+func (e *RestartGameError) Error() string { return e.format(e.Code) }
+
+// Is reports whether target is a *RestartGameError with the same Code.
+func (e *RestartGameError) Is(target error) bool {
+	t, ok := target.(*RestartGameError)
+	return ok && t.Code == e.Code
+}
+
+// StartReplayError is returned when a start_replay request fails. It matches another
+// *StartReplayError with the same Code when using errors.Is.
+type StartReplayError struct {
+	RequestError
+	Code api.ResponseStartReplay_Error
+}
+
+func (e *StartReplayError) Error() string { return e.format(e.Code) }
+
+// Is reports whether target is a *StartReplayError with the same Code.
+func (e *StartReplayError) Is(target error) bool {
+	t, ok := target.(*StartReplayError)
+	return ok && t.Code == e.Code
+}
+
+// ReplayInfoError is returned when a replay_info request fails. It matches another
+// *ReplayInfoError with the same Code when using errors.Is.
+type ReplayInfoError struct {
+	RequestError
+	Code api.ResponseReplayInfo_Error
+}
+
+func (e *ReplayInfoError) Error() string { return e.format(e.Code) }
+
+// Is reports whether target is a *ReplayInfoError with the same Code.
+func (e *ReplayInfoError) Is(target error) bool {
+	t, ok := target.(*ReplayInfoError)
+	return ok && t.Code == e.Code
+}
@@ -0,0 +1,121 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/sc2test"
+)
+
+func TestCreateGameError(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.Handle = func(r *api.Request) *api.Response {
+		if r.GetCreateGame() != nil {
+			return &api.Response{Response: &api.Response_CreateGame{CreateGame: &api.ResponseCreateGame{
+				Error:        api.ResponseCreateGame_InvalidMapPath,
+				ErrorDetails: "no such map",
+			}}}
+		}
+		return nil
+	}
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	err := c.CreateGame("missing.SC2Map", []*api.PlayerSetup{{Type: api.PlayerType_Participant}}, false)
+	if !errors.Is(err, &client.CreateGameError{Code: api.ResponseCreateGame_InvalidMapPath}) {
+		t.Fatalf("expected InvalidMapPath, got %v", err)
+	}
+	if errors.Is(err, &client.CreateGameError{Code: api.ResponseCreateGame_MissingMap}) {
+		t.Error("InvalidMapPath should not match MissingMap")
+	}
+
+	var cge *client.CreateGameError
+	if !errors.As(err, &cge) || cge.Details != "no such map" || cge.Status != api.Status_launched || cge.Request != "CreateGame" {
+		t.Errorf("unexpected error fields: %#v", cge)
+	}
+}
+
+func TestResponseError(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := c.GetObservation()
+	var re *client.ResponseError
+	if !errors.As(err, &re) || re.Request != "Observation" || re.Status != api.Status_launched || len(re.Errors) != 1 {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	if msg := "Observation request failed (status launched): " + re.Errors[0]; err.Error() != msg {
+		t.Errorf("got message %q, expected %q", err.Error(), msg)
+	}
+
+	re.Errors = append(re.Errors, "second")
+	if msg := "Observation request failed (status launched): " + re.Errors[0] + "; second"; re.Error() != msg {
+		t.Errorf("got message %q, expected %q", re.Error(), msg)
+	}
+}
+
+func TestErrorLedger(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+	s.Handle = func(r *api.Request) *api.Response {
+		if r.GetQuery() != nil {
+			return &api.Response{Error: []string{"bad query"}}
+		}
+		return nil
+	}
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Step(8); err != nil {
+		t.Fatal(err)
+	}
+
+	var hooked []client.ClientError
+	c.OnError(func(e client.ClientError) { hooked = append(hooked, e) })
+
+	if c.Query(api.RequestQuery{}) != nil {
+		t.Error("expected a nil query response")
+	}
+	if c.ErrorIf(false, errors.New("ignored")) || !c.ErrorIf(true, errors.New("bot error")) {
+		t.Error("ErrorIf should return the condition")
+	}
+
+	errs := c.GetClientErrors()
+	if len(errs) != 2 || len(hooked) != 2 {
+		t.Fatalf("expected 2 errors, got %v (hooked %v)", errs, hooked)
+	}
+	var re *client.ResponseError
+	if errs[0].Request != "Query" || errs[0].GameLoop != 8 || !errors.As(errs[0], &re) {
+		t.Errorf("unexpected query error: %#v", errs[0])
+	}
+	if errs[1].Request != "" || errs[1].Err.Error() != "bot error" {
+		t.Errorf("unexpected bot error: %#v", errs[1])
+	}
+	if p := c.GetProtocolErrors(); len(p) != 1 || p[0] != "bad query" {
+		t.Errorf("unexpected protocol errors: %v", p)
+	}
+
+	c.ClearClientErrors()
+	c.ClearProtocolErrors()
+	if len(c.GetClientErrors()) != 0 || len(c.GetProtocolErrors()) != 0 {
+		t.Error("expected the ledger to be empty")
+	}
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ClientError is a failure that was recorded instead of being returned to the bot, such as a
+// failed Query or SendActions call.
+type ClientError struct {
+	Request  string // request that failed (e.g. "Query"), empty for errors raised with ErrorIf
+	GameLoop uint32 // game loop of the last observation when the error happened
+	Err      error
+}
+
+func (e ClientError) Error() string {
+	if e.Request == "" {
+		return fmt.Sprintf("[%v] %v", e.GameLoop, e.Err)
+	}
+	return fmt.Sprintf("[%v] %v: %v", e.GameLoop, e.Request, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ClientError) Unwrap() error {
+	return e.Err
+}
+
+// GetClientErrors returns the errors recorded since the last ClearClientErrors.
+func (c *Client) GetClientErrors() []ClientError {
+	c.errorsMu.Lock()
+	defer c.errorsMu.Unlock()
+
+	errs := make([]ClientError, len(c.clientErrors))
+	copy(errs, c.clientErrors)
+	return errs
+}
+
+// GetProtocolErrors returns the error strings reported by the game in recorded responses since
+// the last ClearProtocolErrors.
+func (c *Client) GetProtocolErrors() []string {
+	c.errorsMu.Lock()
+	defer c.errorsMu.Unlock()
+
+	errs := make([]string, len(c.protocolErrors))
+	copy(errs, c.protocolErrors)
+	return errs
+}
+
+// ClearClientErrors empties the client error ledger.
+func (c *Client) ClearClientErrors() {
+	c.errorsMu.Lock()
+	defer c.errorsMu.Unlock()
+	c.clientErrors = nil
+}
+
+// ClearProtocolErrors empties the protocol error ledger.
+func (c *Client) ClearProtocolErrors() {
+	c.errorsMu.Lock()
+	defer c.errorsMu.Unlock()
+	c.protocolErrors = nil
+}
+
+// ErrorIf records err in the ledger if condition is true and returns condition.
+func (c *Client) ErrorIf(condition bool, err error) bool {
+	if condition {
+		c.recordError("", err)
+	}
+	return condition
+}
+
+// OnError registers a callback that is called with each error as it is recorded.
+func (c *Client) OnError(callback func(ClientError)) {
+	if callback != nil {
+		c.onError = append(c.onError, callback)
+	}
+}
+
+// recordError logs err and adds it to the ledger.
+func (c *Client) recordError(request string, err error) {
+	e := ClientError{
+		Request:  request,
+		GameLoop: c.observation.GetObservation().GetGameLoop(),
+		Err:      err,
+	}
+	c.Logger().Error("Request failed", "request", request, "err", err)
+
+	c.errorsMu.Lock()
+	c.clientErrors = append(c.clientErrors, e)
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		c.protocolErrors = append(c.protocolErrors, respErr.Errors...)
+	}
+	c.errorsMu.Unlock()
+
+	for _, cb := range c.onError {
+		cb(e)
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// Logger returns the client's structured logger. Every record is tagged with the player ID, game
+// loop, map and opponent of the current game. Records go to the default slog handler unless
+// another handler is set with SetLogHandler or SetLogOutput.
+func (c *Client) Logger() *slog.Logger {
+	if c.connection.logger == nil {
+		c.SetLogHandler(slog.Default().Handler())
+	}
+	return c.connection.logger
+}
+
+// SetLogHandler routes the client's log records to h.
+func (c *Client) SetLogHandler(h slog.Handler) {
+	c.connection.logger = slog.New(&gameHandler{h, c})
+}
+
+// SetLogOutput routes the client's log records to w in text format (e.g. to a file per game).
+func (c *Client) SetLogOutput(w io.Writer) {
+	c.SetLogHandler(slog.NewTextHandler(w, nil))
+}
+
+// opponent returns a short description of the other player(s) in the game.
+func (c *Client) opponent() string {
+	opponent := ""
+	for _, pi := range c.gameInfo.GetPlayerInfo() {
+		if pi.GetPlayerId() == c.playerID || pi.GetType() == api.PlayerType_Observer {
+			continue
+		}
+		name := pi.GetPlayerName()
+		if name == "" {
+			name = pi.GetRaceRequested().String()
+			if pi.GetDifficulty() != api.Difficulty_nil {
+				name = fmt.Sprintf("%v(%v)", name, pi.GetDifficulty())
+			}
+		}
+		if opponent != "" {
+			opponent += ","
+		}
+		opponent += name
+	}
+	return opponent
+}
+
+// gameHandler adds the current game state to each record.
+type gameHandler struct {
+	slog.Handler
+	c *Client
+}
+
+func (h *gameHandler) Handle(ctx context.Context, r slog.Record) error {
+	c := h.c
+	if c.playerID != 0 {
+		r.AddAttrs(slog.Int("player", int(c.playerID)))
+	}
+	if c.observation != nil {
+		r.AddAttrs(slog.Int("loop", int(c.observation.GetObservation().GetGameLoop())))
+	}
+	if name := c.gameInfo.GetMapName(); name != "" {
+		r.AddAttrs(slog.String("map", name))
+	}
+	if opponent := c.opponent(); opponent != "" {
+		r.AddAttrs(slog.String("opponent", opponent))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *gameHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &gameHandler{h.Handler.WithAttrs(attrs), h.c}
+}
+
+func (h *gameHandler) WithGroup(name string) slog.Handler {
+	return &gameHandler{h.Handler.WithGroup(name), h.c}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// PerfStats are the performance counters collected over one perf interval. Durations are
+// totals for the interval, use PerFrame to get averages.
+type PerfStats struct {
+	PlayerID api.PlayerID  `json:"player_id"`
+	GameLoop uint32        `json:"game_loop"` // game loop at the end of the interval
+	Frames   uint32        `json:"frames"`    // game loops covered by the interval
+	Elapsed  time.Duration `json:"elapsed"`   // wall-clock time of the interval
+
+	BeforeStep  time.Duration `json:"before_step"`
+	Step        time.Duration `json:"step"`
+	Observation time.Duration `json:"observation"`
+	AfterStep   time.Duration `json:"after_step"`
+
+	Actions          int `json:"actions"`
+	MaxActions       int `json:"max_actions"` // most actions completed in a single observation
+	ActionsCompleted int `json:"actions_completed"`
+	ObserverActions  int `json:"observer_actions"`
+	DebugCommands    int `json:"debug_commands"`
+}
+
+// PerFrame returns d averaged over the frames in the interval.
+func (s PerfStats) PerFrame(d time.Duration) time.Duration {
+	if s.Frames == 0 {
+		return 0
+	}
+	return d / time.Duration(s.Frames)
+}
+
+// Add accumulates other into s (keeping the latest player/game loop and the largest MaxActions).
+func (s *PerfStats) Add(other PerfStats) {
+	s.PlayerID = other.PlayerID
+	s.GameLoop = other.GameLoop
+	s.Frames += other.Frames
+	s.Elapsed += other.Elapsed
+	s.BeforeStep += other.BeforeStep
+	s.Step += other.Step
+	s.Observation += other.Observation
+	s.AfterStep += other.AfterStep
+	s.Actions += other.Actions
+	if other.MaxActions > s.MaxActions {
+		s.MaxActions = other.MaxActions
+	}
+	s.ActionsCompleted += other.ActionsCompleted
+	s.ObserverActions += other.ObserverActions
+	s.DebugCommands += other.DebugCommands
+}
+
+// MetricsSink receives performance stats once every perf interval (see SetPerfInterval).
+type MetricsSink interface {
+	ReportPerf(stats PerfStats)
+}
+
+// MetricsSinkFunc ...
+type MetricsSinkFunc func(PerfStats)
+
+// ReportPerf ...
+func (f MetricsSinkFunc) ReportPerf(stats PerfStats) {
+	f(stats)
+}
+
+// SetMetricsSink replaces the sinks that perf stats are reported to. Without any sinks the
+// stats are drawn as text in the game (see DebugTextSink).
+func (c *Client) SetMetricsSink(sinks ...MetricsSink) {
+	c.metrics = sinks
+}
+
+// DebugTextSink returns a sink that draws the stats as text in the top left corner of the game.
+func (c *Client) DebugTextSink() MetricsSink {
+	return MetricsSinkFunc(c.drawPerf)
+}
+
+func (c *Client) drawPerf(stats PerfStats) {
+	text := "" +
+		fmt.Sprintf("frames:      %v\n", stats.Frames) +
+		fmt.Sprintf("frameTime:   %v\n", stats.PerFrame(stats.Elapsed)) +
+		"\n" +
+		fmt.Sprintf("beforeStep:  %v\n", stats.PerFrame(stats.BeforeStep)) +
+		fmt.Sprintf("step:        %v\n", stats.PerFrame(stats.Step)) +
+		fmt.Sprintf("observation: %v\n", stats.PerFrame(stats.Observation)) +
+		fmt.Sprintf("afterStep:   %v\n", stats.PerFrame(stats.AfterStep)) +
+		"\n" +
+		fmt.Sprintf("actions:     %v/%v\n", stats.ActionsCompleted, stats.Actions) +
+		fmt.Sprintf("maxActions:  %v\n", stats.MaxActions) +
+		fmt.Sprintf("obsActions:  %v\n", stats.ObserverActions) +
+		fmt.Sprintf("debugCmds:   %v\n", stats.DebugCommands) +
+		""
+	text = strings.Replace(text, "µ", "u", -1)
+
+	// The perf text goes first so it is the last thing dropped if the draw is too large, and
+	// lastDraw is left alone so the bot's own draws are redrawn with the next stats as well.
+	perf := &api.DebugCommand{
+		Command: &api.DebugCommand_Draw{
+			Draw: &api.DebugDraw{
+				Text: []*api.DebugText{
+					&api.DebugText{
+						Color:      &api.Color{R: 255, G: 255, B: 255},
+						Text:       text,
+						VirtualPos: &api.Point{X: 0, Y: 0, Z: 0},
+					},
+				},
+			},
+		},
+	}
+	if err := c.sendDebugCommands(context.Background(), append([]*api.DebugCommand{perf}, c.lastDraw...), false); err != nil {
+		c.recordError("Debug", err)
+	}
+}
@@ -1,7 +1,8 @@
 package client
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"runtime"
 )
 
@@ -11,19 +12,28 @@ func recoverPanic() {
 	}
 }
 
-// ReportPanic ...
+// ReportPanic logs the panic and stack trace to the default logger.
 func ReportPanic(p interface{}) {
-	log.Print(p)
+	reportPanic(slog.Default(), p)
+}
+
+// ReportPanic logs the panic and stack trace to the client's logger.
+func (c *Client) ReportPanic(p interface{}) {
+	reportPanic(c.Logger(), p)
+}
+
+func reportPanic(logger *slog.Logger, p interface{}) {
+	logger.Error(fmt.Sprint(p))
 
 	// Nicer format than what debug.PrintStack() gives us
 	var pc [32]uintptr
-	n := runtime.Callers(3, pc[:]) // skip the defer, this func, and runtime.Callers
+	n := runtime.Callers(4, pc[:]) // skip the defer, the exported func, this func, and runtime.Callers
 	for _, pc := range pc[:n] {
 		fn := runtime.FuncForPC(pc)
 		if fn == nil {
 			continue
 		}
 		file, line := fn.FileLine(pc)
-		log.Printf("%v:%v in %v", file, line, fn.Name())
+		logger.Error(fmt.Sprintf("%v:%v in %v", file, line, fn.Name()))
 	}
 }
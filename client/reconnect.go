@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// ReconnectPolicy controls how a Client recovers when the websocket to the game is lost.
+type ReconnectPolicy struct {
+	MaxAttempts    int           // redial attempts per drop, zero disables reconnecting
+	InitialBackoff time.Duration // delay before the first redial (doubles after each failure)
+	MaxBackoff     time.Duration // upper limit on the delay between redials
+}
+
+// DefaultReconnectPolicy retries for roughly a minute before giving up.
+var DefaultReconnectPolicy = ReconnectPolicy{
+	MaxAttempts:    10,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+}
+
+// SetReconnectPolicy enables (or with a zero MaxAttempts disables) automatic reconnects. Once
+// enabled, the first request after a drop redials the game with backoff, pings it and re-syncs
+// the status, game info and observation before continuing. Requests that were pending when the
+// connection dropped still fail with an error matching ErrDisconnected.
+func (c *Client) SetReconnectPolicy(policy ReconnectPolicy) {
+	c.reconnectPolicy = policy
+	if policy.MaxAttempts > 0 {
+		c.connection.reconnect = c.reconnect
+	} else {
+		c.connection.reconnect = nil
+	}
+}
+
+// OnDisconnect registers a callback that is called with the cause whenever the connection
+// to the game is found to be lost.
+func (c *Client) OnDisconnect(callback func(err error)) {
+	if callback != nil {
+		c.disconnect = append(c.disconnect, callback)
+	}
+}
+
+// OnReconnect registers a callback that is called after the connection was re-established
+// and the game state was re-synced.
+func (c *Client) OnReconnect(callback func()) {
+	if callback != nil {
+		c.reconnected = append(c.reconnected, callback)
+	}
+}
+
+// reconnect redials the game using the current policy and re-syncs cached state.
+func (c *Client) reconnect(ctx context.Context) error {
+	cause := c.connection.dropCause()
+	c.Logger().Warn("Connection lost", "err", cause)
+	for _, cb := range c.disconnect {
+		cb(cause)
+	}
+
+	// Prevent recursive reconnects from the requests made below
+	c.connection.reconnect = nil
+	defer func() { c.connection.reconnect = c.reconnect }()
+
+	policy := c.reconnectPolicy
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return &TimeoutError{Request: "Reconnect", Err: ctx.Err()}
+		}
+
+		err := c.connection.Connect(c.connection.address, c.connection.port)
+		if err == nil {
+			if err = c.resync(ctx); err == nil {
+				break
+			}
+		}
+		c.Logger().Warn("Reconnect failed", "attempt", attempt, "err", err)
+
+		if attempt >= policy.MaxAttempts {
+			return &DisconnectedError{Request: "Reconnect", Err: cause}
+		}
+		if backoff *= 2; backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+		if backoff <= 0 {
+			backoff = time.Millisecond
+		}
+	}
+
+	c.Logger().Info("Reconnected", "address", c.connection.address, "port", c.connection.port)
+	for _, cb := range c.reconnected {
+		cb()
+	}
+	return nil
+}
+
+// resync refreshes the cached game info and observation after a reconnect.
+func (c *Client) resync(ctx context.Context) error {
+	if !c.IsInGame() {
+		return nil // nothing to sync, the game status was already updated by the ping
+	}
+
+	gameInfo, err := c.connection.gameInfoContext(ctx, api.RequestGameInfo{})
+	if err != nil {
+		return err
+	}
+	c.setGameInfo(gameInfo)
+
+	observation, err := c.connection.observationContext(ctx, api.RequestObservation{})
+	if err != nil {
+		return err
+	}
+	c.observation = observation
+	return nil
+}
+
+// recovered returns true if err was caused by a dropped connection that has since been
+// re-established, in which case the caller can carry on with the re-synced state.
+func (c *Client) recovered(ctx context.Context, err error) bool {
+	if c.connection.reconnect == nil || !errors.Is(err, ErrDisconnected) {
+		return false
+	}
+	if err := c.connection.reconnect(ctx); err != nil {
+		c.Logger().Error("Unable to recover", "err", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/sc2test"
+)
+
+func TestDisconnectWithoutPolicy(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	s.Drop()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetObservation(); !errors.Is(err, client.ErrDisconnected) {
+			t.Fatalf("expected ErrDisconnected, got %v", err)
+		}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	c.SetReconnectPolicy(client.ReconnectPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	var disconnects, reconnects int
+	c.OnDisconnect(func(error) { disconnects++ })
+	c.OnReconnect(func() { reconnects++ })
+
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Drop()
+	if err := c.Step(4); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Step(4); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Connections() != 2 || disconnects != 1 || reconnects != 1 {
+		t.Errorf("connections: %v, disconnects: %v, reconnects: %v", s.Connections(), disconnects, reconnects)
+	}
+	if loop := c.Observation().GetObservation().GetGameLoop(); loop != s.GameLoop() {
+		t.Errorf("observation out of sync: %v != %v", loop, s.GameLoop())
+	}
+}
+
+func TestReconnectGivesUp(t *testing.T) {
+	s := sc2test.NewServer()
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	c.SetReconnectPolicy(client.ReconnectPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	if err := c.Step(1); !errors.Is(err, client.ErrDisconnected) {
+		t.Errorf("expected ErrDisconnected, got %v", err)
+	}
+}
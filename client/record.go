@@ -0,0 +1,224 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/websocket"
+)
+
+// Transport sends a serialized api.Request to the game and returns the serialized api.Response.
+// Requests are made one at a time and in order. Any error is treated as a lost connection.
+type Transport interface {
+	RoundTrip(request []byte) (response []byte, err error)
+	Close() error
+}
+
+// websocketTransport talks to a running game instance.
+type websocketTransport struct {
+	ws *websocket.Conn
+}
+
+func (t websocketTransport) RoundTrip(request []byte) ([]byte, error) {
+	if err := t.ws.WriteMessage(websocket.BinaryMessage, request); err != nil {
+		return nil, err
+	}
+	_, data, err := t.ws.ReadMessage()
+	return data, err
+}
+
+func (t websocketTransport) Close() error {
+	return t.ws.Close()
+}
+
+// ConnectTransport connects the client to something other than a game instance (such as a
+// Playback). Dropped transports can't be redialed, so reconnects will fail after a drop.
+func (c *Client) ConnectTransport(t Transport) error {
+	return c.connection.connectTransport(t)
+}
+
+// SetRecorder starts recording every request/response pair sent over the next connection. Pass
+// nil to stop recording after the next Connect.
+func (c *Client) SetRecorder(r *Recorder) {
+	c.connection.recorder = r
+}
+
+// Frame is a single recorded request/response pair.
+type Frame struct {
+	Request  string        `json:"request"`         // short request name (e.g. "Step")
+	ID       uint32        `json:"id"`              // request ID
+	Time     time.Time     `json:"time"`            // when the request was sent
+	Duration time.Duration `json:"duration"`        // time until the response was read
+	Sent     []byte        `json:"sent"`            // serialized api.Request
+	Received []byte        `json:"received"`        // serialized api.Response
+	Error    string        `json:"error,omitempty"` // transport error, if any
+}
+
+// Recorder writes frames to an io.Writer as JSON lines.
+type Recorder struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	w      *bufio.Writer
+	closer io.Closer
+	err    error
+}
+
+// NewRecorder returns a recorder that writes to w.
+func NewRecorder(w io.Writer) *Recorder {
+	bw := bufio.NewWriter(w)
+	return &Recorder{enc: json.NewEncoder(bw), w: bw}
+}
+
+// CreateRecording returns a recorder that writes to a new file at path.
+func CreateRecording(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRecorder(f)
+	r.closer = f
+	return r, nil
+}
+
+// Err returns the first error encountered while recording (if any).
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Flush writes any buffered frames to the underlying writer.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.w.Flush()
+	}
+	return r.err
+}
+
+// Close flushes the recording and closes the file if it was opened by CreateRecording.
+func (r *Recorder) Close() error {
+	err := r.Flush()
+	if r.closer != nil {
+		if cerr := r.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+func (r *Recorder) record(req request, start time.Time, data []byte, err error) {
+	f := Frame{
+		Request:  req.name,
+		ID:       req.id,
+		Time:     start,
+		Duration: time.Since(start),
+		Sent:     req.data,
+		Received: data,
+	}
+	if err != nil {
+		f.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.enc.Encode(&f)
+	}
+}
+
+// PlaybackError is returned by a Playback when the client diverges from the recording.
+type PlaybackError struct {
+	Frame    int    // index of the frame that was expected next
+	Expected string // request name in the recording
+	Got      string // request name sent by the client
+}
+
+func (e *PlaybackError) Error() string {
+	if e.Expected == "" {
+		return fmt.Sprintf("playback: %v request after end of recording (%v frames)", e.Got, e.Frame)
+	}
+	return fmt.Sprintf("playback: frame %v: expected %v request, got %v", e.Frame, e.Expected, e.Got)
+}
+
+// Playback is a Transport that replays recorded responses without a running game. Requests
+// must be made in the same order as the recording, which is the case for a deterministic bot.
+type Playback struct {
+	frames []Frame
+	next   int
+}
+
+// NewPlayback reads all frames written by a Recorder from r.
+func NewPlayback(r io.Reader) (*Playback, error) {
+	p := &Playback{}
+	dec := json.NewDecoder(r)
+	for {
+		var f Frame
+		if err := dec.Decode(&f); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		p.frames = append(p.frames, f)
+	}
+	return p, nil
+}
+
+// OpenPlayback reads a recording created by CreateRecording.
+func OpenPlayback(path string) (*Playback, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewPlayback(f)
+}
+
+// Frames returns the recorded frames.
+func (p *Playback) Frames() []Frame {
+	return p.frames
+}
+
+// Remaining returns the number of frames that have not been played back yet.
+func (p *Playback) Remaining() int {
+	return len(p.frames) - p.next
+}
+
+// RoundTrip returns the next recorded response after checking that the request matches.
+func (p *Playback) RoundTrip(data []byte) ([]byte, error) {
+	req := &api.Request{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+	name := "nil"
+	if req.Request != nil {
+		name = requestName(req)
+	}
+
+	if p.next >= len(p.frames) {
+		return nil, &PlaybackError{Frame: p.next, Got: name}
+	}
+	f := p.frames[p.next]
+	if f.Request != name {
+		return nil, &PlaybackError{Frame: p.next, Expected: f.Request, Got: name}
+	}
+	p.next++
+
+	if f.Error != "" {
+		return nil, fmt.Errorf("playback: recorded error: %v", f.Error)
+	}
+	return f.Received, nil
+}
+
+// Close does nothing, the recording can only be played once.
+func (p *Playback) Close() error {
+	return nil
+}
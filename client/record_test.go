@@ -0,0 +1,69 @@
+package client_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/sc2test"
+)
+
+func TestRecordPlayback(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	var buf bytes.Buffer
+	rec := client.NewRecorder(&buf)
+
+	c := &client.Client{}
+	c.SetRecorder(rec)
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	var loops []uint32
+	for i := 0; i < 3; i++ {
+		if err := c.Step(2); err != nil {
+			t.Fatal(err)
+		}
+		loops = append(loops, c.Observation().GetObservation().GetGameLoop())
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replay the same session without a server
+	p, err := client.NewPlayback(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = &client.Client{}
+	if err := c.ConnectTransport(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := c.Step(2); err != nil {
+			t.Fatal(err)
+		}
+		if loop := c.Observation().GetObservation().GetGameLoop(); loop != loops[i] {
+			t.Errorf("step %v: got loop %v, expected %v", i, loop, loops[i])
+		}
+	}
+	if p.Remaining() != 0 {
+		t.Errorf("%v frames were not played back", p.Remaining())
+	}
+
+	// Anything past the recording looks like a dropped connection
+	if _, err := c.GetObservation(); !errors.Is(err, client.ErrDisconnected) {
+		t.Errorf("expected ErrDisconnected, got %v", err)
+	}
+}
@@ -13,13 +13,19 @@ const header = `// Code generated by gen_client. DO NOT EDIT.
 package client
 
 import (
+	"context"
+
 	"github.com/chippydip/go-sc2ai/api"
 )
 `
 
 const methodTemplate = `
 func (c *connection) {{.Arg}}({{.Arg}} api.Request{{.Name}}) (*api.Response{{.Name}}, error) {
-	r, err := c.request(&api.Request{
+	return c.{{.Arg}}Context(context.Background(), {{.Arg}})
+}
+
+func (c *connection) {{.Arg}}Context(ctx context.Context, {{.Arg}} api.Request{{.Name}}) (*api.Response{{.Name}}, error) {
+	r, err := c.request(ctx, &api.Request{
 		Request: &api.Request_{{.Short}}{
 			{{.Short}}: &{{.Arg}},
 		},
@@ -257,7 +257,10 @@ func writeEnum(name string, apiType string, names []string, values map[string]ui
 
 	fmt.Fprint(w2, "// Code generated by gen_ids. DO NOT EDIT.\npackage "+pkgName+
 		"\n\nimport \"github.com/chippydip/go-sc2ai/api\"\n\n"+
-		"func String(e api."+apiType+") string {\n\treturn strings[uint32(e)]\n}\n\nvar strings = map[uint32]string{\n")
+		"func String(e api."+apiType+") string {\n\treturn strings[uint32(e)]\n}\n\n"+
+		"// FromString returns the value with the given name (the inverse of String).\n"+
+		"func FromString(s string) (api."+apiType+", bool) {\n\tfor k, v := range strings {\n\t\tif v == s {\n"+
+		"\t\t\treturn api."+apiType+"(k), true\n\t\t}\n\t}\n\treturn 0, false\n}\n\nvar strings = map[uint32]string{\n")
 
 	maxDigits := int(math.Ceil(math.Log10(float64(maxVal)))) + 1
 	for _, name := range names {
@@ -7,6 +7,16 @@ func String(e api.UpgradeID) string {
 	return strings[uint32(e)]
 }
 
+// FromString returns the value with the given name (the inverse of String).
+func FromString(s string) (api.UpgradeID, bool) {
+	for k, v := range strings {
+		if v == s {
+			return api.UpgradeID(k), true
+		}
+	}
+	return 0, false
+}
+
 var strings = map[uint32]string{
 	0:   "Invalid",
 	1:   "CarrierLaunchSpeedUpgrade",
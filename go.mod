@@ -22,4 +22,4 @@ require (
 	gopkg.in/warnings.v0 v0.1.2 // indirect
 )
 
-go 1.20
+go 1.21
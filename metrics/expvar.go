@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+)
+
+// Snapshot is the JSON document exported by Expvar.
+type Snapshot struct {
+	Games   int                         `json:"games"`
+	Latest  map[string]client.PerfStats `json:"latest"` // by player ID
+	Totals  map[string]client.PerfStats `json:"totals"` // by player ID, for the current game
+	Overall client.PerfStats            `json:"overall"`
+}
+
+// Expvar exports the latest stats and running totals as JSON, both through the expvar package
+// (at /debug/vars on the default mux) and as an http.Handler of its own.
+type Expvar struct {
+	mu      sync.Mutex
+	latest  map[api.PlayerID]client.PerfStats
+	totals  map[api.PlayerID]client.PerfStats
+	overall client.PerfStats
+	games   int
+}
+
+// NewExpvar creates a sink and publishes it under name. Like expvar.Publish, it panics if the
+// name is already in use. An empty name skips publishing.
+func NewExpvar(name string) *Expvar {
+	e := &Expvar{}
+	if name != "" {
+		expvar.Publish(name, expvar.Func(func() interface{} { return e.Snapshot() }))
+	}
+	return e
+}
+
+// ReportPerf implements client.MetricsSink.
+func (e *Expvar) ReportPerf(stats client.PerfStats) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.latest == nil {
+		e.latest = map[api.PlayerID]client.PerfStats{}
+		e.totals = map[api.PlayerID]client.PerfStats{}
+	}
+
+	// A lower game loop means a new game was started
+	if prev, ok := e.latest[stats.PlayerID]; !ok || stats.GameLoop < prev.GameLoop {
+		delete(e.totals, stats.PlayerID)
+		e.games++
+	}
+	e.latest[stats.PlayerID] = stats
+
+	total := e.totals[stats.PlayerID]
+	total.Add(stats)
+	e.totals[stats.PlayerID] = total
+	e.overall.Add(stats)
+}
+
+// Snapshot returns the current state of the exported stats.
+func (e *Expvar) Snapshot() Snapshot {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s := Snapshot{
+		Games:   e.games,
+		Latest:  map[string]client.PerfStats{},
+		Totals:  map[string]client.PerfStats{},
+		Overall: e.overall,
+	}
+	for id, stats := range e.latest {
+		s.Latest[strconv.Itoa(int(id))] = stats
+	}
+	for id, stats := range e.totals {
+		s.Totals[strconv.Itoa(int(id))] = stats
+	}
+	return s
+}
+
+// ServeHTTP writes the current snapshot as JSON.
+func (e *Expvar) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(e.Snapshot()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
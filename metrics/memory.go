@@ -0,0 +1,66 @@
+// Package metrics provides client.MetricsSink implementations for collecting bot performance.
+package metrics
+
+import (
+	"sync"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+)
+
+// Memory keeps reported stats in memory. It is safe to share between clients.
+type Memory struct {
+	// Limit is the maximum number of samples to keep (zero means unlimited).
+	Limit int
+
+	mu      sync.Mutex
+	samples []client.PerfStats
+	totals  map[api.PlayerID]client.PerfStats
+}
+
+// NewMemory returns an empty in-memory sink that keeps at most limit samples.
+func NewMemory(limit int) *Memory {
+	return &Memory{Limit: limit}
+}
+
+// ReportPerf implements client.MetricsSink.
+func (m *Memory) ReportPerf(stats client.PerfStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.samples = append(m.samples, stats)
+	if m.Limit > 0 && len(m.samples) > m.Limit {
+		m.samples = append(m.samples[:0], m.samples[len(m.samples)-m.Limit:]...)
+	}
+
+	if m.totals == nil {
+		m.totals = map[api.PlayerID]client.PerfStats{}
+	}
+	total := m.totals[stats.PlayerID]
+	total.Add(stats)
+	m.totals[stats.PlayerID] = total
+}
+
+// Samples returns a copy of the retained samples, oldest first.
+func (m *Memory) Samples() []client.PerfStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	samples := make([]client.PerfStats, len(m.samples))
+	copy(samples, m.samples)
+	return samples
+}
+
+// Total returns the sum of every sample reported for the player (including dropped ones).
+func (m *Memory) Total(player api.PlayerID) client.PerfStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.totals[player]
+}
+
+// Reset discards all samples and totals.
+func (m *Memory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.samples, m.totals = nil, nil
+}
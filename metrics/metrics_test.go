@@ -0,0 +1,60 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/metrics"
+	"github.com/chippydip/go-sc2ai/sc2test"
+)
+
+func TestSinks(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	mem := metrics.NewMemory(2)
+	exp := metrics.NewExpvar("")
+	c.SetMetricsSink(mem, exp)
+	c.SetPerfInterval(8)
+
+	for i := 0; i < 6; i++ {
+		c.SendActions([]*api.Action{{}})
+		if err := c.Step(4); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	samples := mem.Samples()
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %v", len(samples))
+	}
+	if last := samples[1]; last.GameLoop != 24 || last.Frames != 8 || last.Actions != 2 {
+		t.Errorf("unexpected sample: %+v", last)
+	}
+	if total := mem.Total(0); total.Frames != 24 || total.Actions != 6 {
+		t.Errorf("unexpected total: %+v", total)
+	}
+
+	w := httptest.NewRecorder()
+	exp.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	var snapshot metrics.Snapshot
+	if err := json.NewDecoder(w.Body).Decode(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Games != 1 || snapshot.Overall.Frames != 24 || snapshot.Latest["0"].GameLoop != 24 {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+}
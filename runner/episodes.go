@@ -0,0 +1,121 @@
+package runner
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+)
+
+// EpisodeResult is the outcome of a single game played by RunEpisodes.
+type EpisodeResult struct {
+	Episode   int           // zero-based episode number
+	Restarted bool          // true if the game was restarted instead of created
+	GameLoop  uint32        // final game loop
+	Duration  time.Duration // wall-clock time spent playing
+	Players   []*api.PlayerResult
+}
+
+// Result returns the result for the given player or Result_nil if it isn't known.
+func (r EpisodeResult) Result(playerID api.PlayerID) api.Result {
+	for _, player := range r.Players {
+		if player.GetPlayerId() == playerID {
+			return player.GetResult()
+		}
+	}
+	return api.Result_nil
+}
+
+// RunEpisodes plays up to episodes consecutive games against the same SC2 processes and returns
+// the result of each one. Single player games are restarted when possible, otherwise a new game
+// is created. Each episode runs the agent on a freshly initialized client. Ladder games are
+// managed externally, so only a single episode is played in that case.
+func RunEpisodes(agent client.PlayerSetup, episodes int) []EpisodeResult {
+	if !loadSettings() {
+		return nil
+	}
+
+	config := newAgentConfig(agent)
+	if ladderGamePort > 0 {
+		config.joinLadderGame()
+		config.openLogs("game")
+		defer config.closeLogs()
+		result := config.runEpisode(0, false)
+		for _, c := range config.clients {
+			printResult(c)
+		}
+		return []EpisodeResult{result}
+	}
+	config.launchStarcraft()
+	defer config.closeLogs()
+
+	var results []EpisodeResult
+	for i := 0; i < episodes; i++ {
+		restarted := i > 0 && config.restartGame()
+		if !restarted {
+			if i > 0 {
+				config.leaveGame()
+			}
+			config.startGame(mapPath())
+		}
+
+		config.openLogs(fmt.Sprintf("episode%v", i+1))
+		result := config.runEpisode(i, restarted)
+		c := config.clients[0]
+		c.Logger().Info("Episode finished", "episode", i, "result", result.Result(c.PlayerID()), "duration", result.Duration)
+		results = append(results, result)
+	}
+
+	config.leaveGame()
+	return results
+}
+
+// restartGame tries to restart a single player game and returns true on success.
+func (config *gameConfig) restartGame() bool {
+	if len(config.clients) != 1 {
+		return false // only single player games can be restarted
+	}
+	if err := config.clients[0].RestartGame(); err != nil {
+		config.clients[0].Logger().Warn("Unable to restart game", "err", err)
+		return false
+	}
+	return true
+}
+
+// leaveGame makes every client leave its current game (if any).
+func (config *gameConfig) leaveGame() {
+	for _, c := range config.clients {
+		if c.Status == api.Status_in_game || c.Status == api.Status_ended {
+			if err := c.RequestLeaveGame(); err != nil {
+				c.Logger().Error("Unable to leave game", "err", err)
+			}
+		}
+	}
+}
+
+// runEpisode runs the agents until the game ends without leaving it.
+func (config *gameConfig) runEpisode(episode int, restarted bool) EpisodeResult {
+	start := time.Now()
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(config.clients))
+	for _, c := range config.clients {
+		c.ClearCallbacks() // agents from earlier episodes must not see this one
+		go func(c *client.Client) {
+			defer wg.Done()
+			runAgent(c)
+		}(c)
+	}
+	wg.Wait()
+
+	obs := config.clients[0].Observation()
+	return EpisodeResult{
+		Episode:   episode,
+		Restarted: restarted,
+		GameLoop:  obs.GetObservation().GetGameLoop(),
+		Duration:  time.Since(start),
+		Players:   obs.GetPlayerResult(),
+	}
+}
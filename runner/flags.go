@@ -2,7 +2,7 @@ package runner
 
 import (
 	"flag"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 )
@@ -10,7 +10,7 @@ import (
 // Set changes the default value of a command line flag.
 func Set(name, value string) {
 	if err := flag.Set(name, value); err != nil {
-		log.Print(err)
+		slog.Warn("Unable to set flag", "name", name, "err", err)
 	}
 }
 
@@ -30,7 +30,7 @@ func loadSettings() bool {
 	}
 
 	if !hasProcessPath() {
-		log.Println("Can't find executable path, hope that it's ok. If not, " +
+		slog.Warn("Can't find executable path, hope that it's ok. If not, " +
 			"please run StarCraft II first or use the --executable <path> arg")
 	}
 
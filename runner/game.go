@@ -1,7 +1,8 @@
 package runner
 
 import (
-	"log"
+	"log/slog"
+	"os"
 
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/client"
@@ -16,6 +17,7 @@ type gameConfig struct {
 	clients  []*client.Client
 	started  bool
 	lastPort int
+	logFiles []*os.File
 }
 
 func newGameConfig(participants ...client.PlayerSetup) *gameConfig {
@@ -27,6 +29,7 @@ func newGameConfig(participants ...client.PlayerSetup) *gameConfig {
 		nil,
 		false,
 		0,
+		nil,
 	}
 
 	for _, p := range participants {
@@ -40,20 +43,20 @@ func newGameConfig(participants ...client.PlayerSetup) *gameConfig {
 
 func (config *gameConfig) startGame(mapPath string) {
 	if !config.createGame(mapPath) {
-		log.Fatal("Failed to create game.")
+		fatal(slog.Default(), "Failed to create game")
 	}
 	config.joinGame()
 }
 
 func (config *gameConfig) createGame(mapPath string) bool {
 	if !config.started {
-		log.Panic("Game not started")
+		panic("Game not started")
 	}
 
 	// Create with the first client
 	err := config.clients[0].CreateGame(mapPath, config.playerSetup, processRealtime)
 	if err != nil {
-		log.Print(err)
+		config.clients[0].Logger().Error("Unable to create game", "err", err)
 		return false
 	}
 	return true
@@ -62,8 +65,8 @@ func (config *gameConfig) createGame(mapPath string) bool {
 func (config *gameConfig) joinGame() bool {
 	// TODO: Make this parallel and get rid of the WaitJoinGame method
 	for i, client := range config.clients {
-		if err := client.RequestJoinGame(config.playerSetup[i], processInterfaceOptions, config.ports); err != nil {
-			log.Fatalf("Unable to join game: %v", err)
+		if err := client.RequestJoinGame(config.playerSetup[i], interfaceOptions(), config.ports); err != nil {
+			fatal(client.Logger(), "Unable to join game", "err", err)
 		}
 	}
 
@@ -112,7 +115,7 @@ func (config *gameConfig) connect(port int) {
 		pi := config.processInfo[i]
 
 		if err := client.Connect(config.netAddress, pi.Port, processConnectTimeout); err != nil {
-			log.Panic("Failed to connect")
+			panic("Failed to connect")
 		}
 	}
 
@@ -1,7 +1,7 @@
 package runner
 
 import (
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -38,7 +38,7 @@ func (config *gameConfig) reLaunchStarcraft() {
 
 func (config *gameConfig) launchStarcraft() {
 	if len(config.clients) == 0 {
-		log.Panic("No agents set")
+		panic("No agents set")
 	}
 
 	portStart := 0
@@ -66,9 +66,9 @@ func (config *gameConfig) launchProcesses(clients []*client.Client) []client.Pro
 	// Make sure we have a valid executable path
 	path := processPathForBuild(launchBaseBuild)
 	if _, err := os.Stat(path); err != nil {
-		log.Print("Executable path can't be found, try running the StarCraft II executable first.")
+		slog.Warn("Executable path can't be found, try running the StarCraft II executable first.")
 		if len(path) > 0 {
-			log.Printf("%v does not exist on your filesystem.", path)
+			slog.Warn("Executable does not exist on your filesystem", "path", path)
 		}
 	}
 
@@ -116,14 +116,14 @@ func (config *gameConfig) launchAndAttach(path string, c *client.Client) client.
 		pi.Path = path
 		pi.PID = startProcess(pi.Path, args)
 		if pi.PID == 0 {
-			log.Print("Unable to start sc2 executable with path: ", pi.Path)
+			c.Logger().Error("Unable to start sc2 executable", "path", pi.Path)
 		} else {
-			log.Printf("Launched SC2 (%v), PID: %v", pi.Path, pi.PID)
+			c.Logger().Info("Launched SC2", "path", pi.Path, "pid", pi.PID)
 		}
 
 		// Attach
 		if err := c.Connect(config.netAddress, pi.Port, processConnectTimeout); err != nil {
-			log.Panic("Failed to connect")
+			panic("Failed to connect")
 		}
 	}
 
@@ -147,7 +147,7 @@ func startProcess(path string, args []string) int {
 	}
 
 	if err := cmd.Start(); err != nil {
-		log.Print(err)
+		slog.Error("Unable to start process", "path", path, "err", err)
 		return 0
 	}
 
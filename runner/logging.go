@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	logDir = ""
+)
+
+func init() {
+	flagStr("logDir", &logDir, "Directory to write a separate log file for each game and player to")
+}
+
+// SetLogDir sets the default directory for per-game log files.
+func SetLogDir(dir string) {
+	Set("logDir", dir)
+}
+
+// fatal logs the error and exits.
+func fatal(logger *slog.Logger, msg string, args ...interface{}) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
+
+// openLogs routes each client's log output to a new file for the game that is about to start.
+func (config *gameConfig) openLogs(game string) {
+	config.closeLogs()
+	if logDir == "" {
+		return
+	}
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		slog.Error("Unable to create log directory", "dir", logDir, "err", err)
+		return
+	}
+
+	start := time.Now().Format("20060102-150405")
+	for i, c := range config.clients {
+		path := filepath.Join(logDir, fmt.Sprintf("%v-%v-%v.log", start, game, i+1))
+		f, err := os.Create(path)
+		if err != nil {
+			slog.Error("Unable to create log file", "path", path, "err", err)
+			continue
+		}
+		c.SetLogOutput(f)
+		config.logFiles = append(config.logFiles, f)
+	}
+}
+
+// closeLogs closes any log files opened by openLogs.
+func (config *gameConfig) closeLogs() {
+	if len(config.logFiles) == 0 {
+		return
+	}
+	for _, c := range config.clients {
+		c.SetLogHandler(slog.Default().Handler())
+	}
+	for _, f := range config.logFiles {
+		f.Close()
+	}
+	config.logFiles = nil
+}
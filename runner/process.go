@@ -3,7 +3,7 @@ package runner
 import (
 	"fmt"
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"os/user"
@@ -21,12 +21,11 @@ var (
 	processInterfaceOptions = &api.InterfaceOptions{
 		Raw:   true,
 		Score: true,
-		// FeatureLayer: &api.SpatialCameraSetup{
-		// 	Resolution: &api.Size2DI{X: 512, Y: 512},
-		// },
 	}
 	processRealtime          = false
 	processConnectTimeout, _ = time.ParseDuration("2m")
+	processFeatureScreen     = api.Size2DI{}
+	processFeatureMinimap    = api.Size2DI{}
 )
 
 func init() {
@@ -35,6 +34,8 @@ func init() {
 	//flagInt("port", &processSettings.portStart, "The port to make StarCraft II listen on.")
 	flagBool("realtime", &processRealtime, "Whether to run StarCraft II in real time or not.")
 	flagDur("timeout", &processConnectTimeout, "Timeout for how long the library will block for a response.")
+	flagVar("featureScreen", (*sizeFlag)(&processFeatureScreen), "Resolution of the feature layer screen (e.g. 84x84), enables feature layers.")
+	flagVar("featureMinimap", (*sizeFlag)(&processFeatureMinimap), "Resolution of the feature layer minimap (e.g. 64x64), enables feature layers.")
 }
 
 // SetExecutable sets the default executable path to use.
@@ -57,29 +58,84 @@ func SetInterfaceOptions(options *api.InterfaceOptions) {
 	processInterfaceOptions = options
 }
 
+// SetFeatureLayer sets the default feature layer resolutions. Feature layers are requested in
+// addition to the other interface options if either resolution is non-zero.
+func SetFeatureLayer(screen, minimap api.Size2DI) {
+	Set("featureScreen", (*sizeFlag)(&screen).String())
+	Set("featureMinimap", (*sizeFlag)(&minimap).String())
+}
+
+// interfaceOptions returns the interface options with the feature layer setup (if enabled).
+func interfaceOptions() *api.InterfaceOptions {
+	if processInterfaceOptions.GetFeatureLayer() != nil {
+		return processInterfaceOptions // explicitly set by SetInterfaceOptions
+	}
+
+	screen, minimap := processFeatureScreen, processFeatureMinimap
+	if screen == (api.Size2DI{}) && minimap == (api.Size2DI{}) {
+		return processInterfaceOptions
+	}
+
+	// The game requires both resolutions, so use the common defaults for a missing one
+	if screen == (api.Size2DI{}) {
+		screen = api.Size2DI{X: 84, Y: 84}
+	}
+	if minimap == (api.Size2DI{}) {
+		minimap = api.Size2DI{X: 64, Y: 64}
+	}
+
+	options := *processInterfaceOptions
+	options.FeatureLayer = &api.SpatialCameraSetup{
+		Width:             24,
+		Resolution:        &screen,
+		MinimapResolution: &minimap,
+	}
+	return &options
+}
+
+type sizeFlag api.Size2DI
+
+func (f *sizeFlag) Set(value string) error {
+	var x, y int32
+	if value != "" {
+		if _, err := fmt.Sscanf(value, "%dx%d", &x, &y); err != nil || x < 0 || y < 0 {
+			return fmt.Errorf("Invalid size: %v", value)
+		}
+	}
+	*f = sizeFlag{X: x, Y: y}
+	return nil
+}
+
+func (f *sizeFlag) String() string {
+	if f == nil || (f.X == 0 && f.Y == 0) {
+		return ""
+	}
+	return fmt.Sprintf("%dx%d", f.X, f.Y)
+}
+
 func defaultExecutable() string {
 	path := ""
 
 	// Default to the environment variable (Linux mostly)
 	if sc2path := os.Getenv("SC2PATH"); len(sc2path) > 0 {
-		log.Printf("SC2PATH: %v", sc2path)
+		slog.Info("Using SC2PATH", "path", sc2path)
 		path = filepath.Join(sc2path, "Versions", "dummy")
 	}
 
 	// Read value from ExecuteInfo.txt if the current user has run the game before
 	file, err := getUserDirectory()
 	if err != nil {
-		log.Printf("Error getting user directory: %v", err)
+		slog.Warn("Error getting user directory", "err", err)
 	} else if len(file) > 0 {
 		file = filepath.Join(file, "Starcraft II", "ExecuteInfo.txt")
-		log.Printf("ExecuteInfo path: %v", file)
+		slog.Info("Reading ExecuteInfo", "path", file)
 	}
 
 	if props, err := newPropertyReader(file); err == nil {
 		props.getString("executable", &path)
-		log.Printf("  executable = %v", path)
+		slog.Info("Found executable", "path", path)
 	} else {
-		log.Printf("Error reading `executable`: %v", err)
+		slog.Warn("Error reading `executable`", "err", err)
 	}
 
 	// Backout the defaulted path to the Versions directory and then find the latest Base game
@@ -110,7 +166,7 @@ func processPathForBuild(build uint32) string {
 	if build != 0 {
 		root := sc2Path(path)
 		if root == "" {
-			log.Printf("Can't find game dir: %v", path)
+			slog.Warn("Can't find game dir", "path", path)
 		}
 		dir := filepath.Join(sc2Path(path), "Versions")
 		exe := sc2Exe(path)
@@ -118,7 +174,7 @@ func processPathForBuild(build uint32) string {
 		// Get the path of the correct version and make sure the exe exists
 		path = filepath.Join(dir, fmt.Sprintf("Base%v", build), exe)
 		if _, err := os.Stat(path); err != nil {
-			log.Printf("Base version not found: %v", err)
+			slog.Warn("Base version not found", "err", err)
 		}
 	}
 	return path
@@ -133,7 +189,7 @@ func getUserDirectory() (string, error) {
 
 		sout := strings.TrimSpace(string(out))
 		if err != nil {
-			log.Print("Documents directory lookup failed: ", sout)
+			slog.Warn("Documents directory lookup failed", "output", sout)
 			return "", err
 		}
 
@@ -145,7 +201,7 @@ func getUserDirectory() (string, error) {
 	case "darwin":
 		user, err := user.Current()
 		if err != nil {
-			log.Print("Failed to get current user:", err)
+			slog.Warn("Failed to get current user", "err", err)
 			return "", err
 		}
 		return filepath.Join(user.HomeDir, "Library", "Application Support", "Blizzard"), nil
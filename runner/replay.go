@@ -2,7 +2,7 @@ package runner
 
 import (
 	"io/ioutil"
-	"log"
+	"log/slog"
 	"path/filepath"
 	"strings"
 
@@ -21,7 +21,7 @@ var (
 func SetReplayPath(path string) error {
 	replayFiles = nil
 	if p, err := filepath.Abs(path); err != nil {
-		log.Printf("Failed to get absolute path: %v", err)
+		slog.Warn("Failed to get absolute path", "err", err)
 	} else {
 		path = p
 	}
@@ -92,44 +92,44 @@ func startReplay(config *gameConfig, path string) bool {
 	// Get info about the replay
 	info, err := config.clients[0].RequestReplayInfo(path)
 	if err != nil {
-		log.Printf("Unable to get replay info: %v", err)
+		config.clients[0].Logger().Error("Unable to get replay info", "path", path, "err", err)
 		return false
 	}
 
 	// Allow the bot user to skip certain replays after looking at the info
 	if replayFilter != nil && !replayFilter(info) {
-		log.Printf("Skipping replay: %v", path)
+		config.clients[0].Logger().Info("Skipping replay", "path", path)
 		return false
 	}
 
 	// Check if we need to re-launch the game
 	current := config.clients[0].Proto()
 	if info.GetBaseBuild() != current.GetBaseBuild() || info.GetDataVersion() != current.GetDataVersion() {
-		log.Printf("Version mis-match, relaunching client")
+		config.clients[0].Logger().Info("Version mis-match, relaunching client")
 		SetGameVersion(info.GetBaseBuild(), info.GetDataVersion())
 
 		config.reLaunchStarcraft()
 
 		current = config.clients[0].Proto()
 		if info.GetBaseBuild() != current.GetBaseBuild() {
-			log.Fatalf("Failed to launch correct base build: %v %v", current.GetBaseBuild(), info.GetBaseBuild())
+			fatal(config.clients[0].Logger(), "Failed to launch correct base build", "current", current.GetBaseBuild(), "replay", info.GetBaseBuild())
 		}
 		if info.GetDataVersion() != current.GetDataVersion() {
-			log.Fatalf("Failed to launch correct data version: %v %v", current.GetDataVersion(), info.GetDataVersion())
+			fatal(config.clients[0].Logger(), "Failed to launch correct data version", "current", current.GetDataVersion(), "replay", info.GetDataVersion())
 		}
 	}
 
-	log.Printf("Launching replay: %v", path)
+	config.clients[0].Logger().Info("Launching replay", "path", path)
 	err = config.clients[0].RequestStartReplay(api.RequestStartReplay{
 		Replay: &api.RequestStartReplay_ReplayPath{
 			ReplayPath: path,
 		},
 		ObservedPlayerId: replayObservedPlayer,
-		Options:          processInterfaceOptions,
+		Options:          interfaceOptions(),
 		Realtime:         processRealtime,
 	})
 	if err != nil {
-		log.Fatalf("Unable to start replay: %v", err)
+		fatal(config.clients[0].Logger(), "Unable to start replay", "err", err)
 	}
 
 	return true
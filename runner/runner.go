@@ -1,7 +1,7 @@
 package runner
 
 import (
-	"log"
+	"log/slog"
 	"sync"
 
 	"github.com/chippydip/go-sc2ai/client"
@@ -36,21 +36,9 @@ func RunAgent(agent client.PlayerSetup) {
 	// fmt.Println(gamePort, startPort, ladderServer, computerOpponent, computerRace, computerDifficulty)
 	// fmt.Println(processSettings, gameSettings)
 
-	var numAgents = 1
-	var config *gameConfig
-	if computerOpponent && ladderGamePort == 0 {
-		config = newGameConfig(agent, client.NewComputer(computerRace, computerDifficulty, computerBuild))
-	} else {
-		numAgents = 2
-		config = newGameConfig(agent)
-	}
-
+	config := newAgentConfig(agent)
 	if ladderGamePort > 0 {
-		log.Print("Connecting to port ", ladderGamePort)
-		config.connect(ladderGamePort)
-		config.setupPorts(numAgents, ladderStartPort, false)
-		config.joinGame()
-		log.Print(" Successfully joined game")
+		config.joinLadderGame()
 	} else {
 		config.launchStarcraft()
 
@@ -61,9 +49,28 @@ func RunAgent(agent client.PlayerSetup) {
 		config.startGame(mapPath())
 	}
 
+	config.openLogs("game")
+	defer config.closeLogs()
 	run(config.clients)
 }
 
+// newAgentConfig sets up the agent against either a computer or another (ladder) bot.
+func newAgentConfig(agent client.PlayerSetup) *gameConfig {
+	if computerOpponent && ladderGamePort == 0 {
+		return newGameConfig(agent, client.NewComputer(computerRace, computerDifficulty, computerBuild))
+	}
+	return newGameConfig(agent)
+}
+
+// joinLadderGame connects to a game that was created by the ladder manager.
+func (config *gameConfig) joinLadderGame() {
+	slog.Info("Connecting to ladder game", "port", ladderGamePort)
+	config.connect(ladderGamePort)
+	config.setupPorts(2, ladderStartPort, false)
+	config.joinGame()
+	slog.Info("Successfully joined game")
+}
+
 func run(clients []*client.Client) {
 	wg := sync.WaitGroup{}
 	wg.Add(len(clients))
@@ -83,13 +90,13 @@ func run(clients []*client.Client) {
 func runAgent(c *client.Client) {
 	defer func() {
 		if p := recover(); p != nil {
-			client.ReportPanic(p)
+			c.ReportPanic(p)
 		}
 
 		// If the bot crashed before losing, keep the game running (force the opponent to earn the win)
 		for c.IsInGame() {
 			if err := c.Step(224); err != nil { // 10 seconds per update
-				log.Print(err)
+				c.Logger().Error("Step failed", "err", err)
 				break
 			}
 		}
@@ -97,13 +104,13 @@ func runAgent(c *client.Client) {
 
 	// get GameInfo, Data, and Observation
 	if err := c.Init(); err != nil {
-		log.Printf("Failed to init client: %v", err)
+		c.Logger().Error("Failed to init client", "err", err)
 		return
 	}
 
 	// make sure the bot was added to a game or replay
 	if !c.IsInGame() {
-		log.Print("Client is not in-game")
+		c.Logger().Error("Client is not in-game")
 		return
 	}
 
@@ -116,11 +123,14 @@ func cleanup(c *client.Client) {
 		// Leave the game (but only in non-ladder games)
 		c.RequestLeaveGame()
 	}
+	printResult(c)
+}
 
+func printResult(c *client.Client) {
 	// Print the winner
 	for _, player := range c.Observation().GetPlayerResult() {
 		if player.GetPlayerId() == c.PlayerID() {
-			log.Print(player.GetResult())
+			c.Logger().Info("Game over", "result", player.GetResult())
 		}
 	}
 }
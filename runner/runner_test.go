@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/sc2test"
+)
+
+func TestRunAgentLadder(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.EndLoop = 22.4 * 60
+
+	// Pretend the flags were parsed and a ladder manager created the game
+	hasLoaded = true
+	ladderGamePort = s.Port()
+	defer func() { hasLoaded, ladderGamePort = false, 0 }()
+
+	steps := 0
+	agent := client.AgentFunc(func(info client.AgentInfo) {
+		for info.IsInGame() {
+			if err := info.Step(16); err != nil {
+				t.Error(err)
+				return
+			}
+			steps++
+		}
+	})
+	RunAgent(client.NewParticipant(api.Race_Protoss, agent, "test"))
+
+	if steps != 84 {
+		t.Errorf("expected 84 steps, got %v", steps)
+	}
+	if s.Status() != api.Status_ended {
+		t.Errorf("expected game to end, status: %v", s.Status())
+	}
+}
+
+func TestRunEpisodes(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.EndLoop = 224
+	s.Results = []*api.PlayerResult{{PlayerId: 1, Result: api.Result_Victory}}
+
+	// Pretend the flags were parsed and SC2 is already listening
+	hasLoaded = true
+	computerOpponent = true
+	launchPortStart = s.Port() + 1
+	defer func() { hasLoaded, computerOpponent, launchPortStart = false, false, 8168 }()
+
+	// Callbacks registered in one episode must not run in the next
+	var episode int
+	var steps, afterSteps [3]int
+	agent := client.AgentFunc(func(info client.AgentInfo) {
+		mine := episode
+		episode++
+		info.OnAfterStep(func() { afterSteps[mine]++ })
+		for info.IsInGame() {
+			if err := info.Step(16); err != nil {
+				t.Error(err)
+				return
+			}
+			if info.IsInGame() {
+				steps[mine]++ // the step that ends the game skips the callbacks
+			}
+		}
+	})
+	results := RunEpisodes(client.NewParticipant(api.Race_Protoss, agent, "test"), 3)
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %v", len(results))
+	}
+	for i, r := range results {
+		if r.Episode != i || r.Restarted != (i > 0) || r.GameLoop != 224 || r.Result(1) != api.Result_Victory {
+			t.Errorf("unexpected result: %+v", r)
+		}
+	}
+
+	for i, n := range afterSteps {
+		if n == 0 || n != steps[i] {
+			t.Errorf("episode %v: got %v after step callbacks for %v steps", i, n, steps[i])
+		}
+	}
+
+	var creates, restarts int
+	for _, r := range s.Requests() {
+		if r.GetCreateGame() != nil {
+			creates++
+		}
+		if r.GetRestartGame() != nil {
+			restarts++
+		}
+	}
+	if creates != 1 || restarts != 2 {
+		t.Errorf("expected 1 create and 2 restarts, got %v and %v", creates, restarts)
+	}
+	if s.Status() != api.Status_launched {
+		t.Errorf("expected to leave the game, status: %v", s.Status())
+	}
+}
+
+func TestFeatureLayerOptions(t *testing.T) {
+	defer func() { processFeatureScreen, processFeatureMinimap = api.Size2DI{}, api.Size2DI{} }()
+
+	if interfaceOptions().GetFeatureLayer() != nil {
+		t.Error("feature layers should be disabled by default")
+	}
+
+	if err := flag.Set("featureScreen", "96x72"); err != nil {
+		t.Fatal(err)
+	}
+	setup := interfaceOptions().GetFeatureLayer()
+	if setup.GetResolution().X != 96 || setup.GetResolution().Y != 72 || setup.GetMinimapResolution().X != 64 {
+		t.Errorf("unexpected feature layer setup: %v", setup)
+	}
+	if processInterfaceOptions.GetFeatureLayer() != nil {
+		t.Error("default interface options should not be modified")
+	}
+}
@@ -0,0 +1,463 @@
+// Package sc2test provides an in-process fake of the StarCraft II websocket API so that
+// clients, runners and bots can be exercised in tests without launching the game.
+package sc2test
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/websocket"
+)
+
+// Server serves the /sc2api websocket protocol and answers requests with scripted responses.
+// The exported fields should be configured before any client connects.
+type Server struct {
+	// Ping is returned for every ping request.
+	Ping api.ResponsePing
+
+	// GameInfo and Data are returned for game_info and data requests.
+	GameInfo *api.ResponseGameInfo
+	Data     *api.ResponseData
+
+	// Observe builds the observation for the given game loop. The GameLoop and PlayerResult
+	// fields are filled in by the server. If nil, an empty observation is returned.
+	Observe func(gameLoop uint32) *api.ResponseObservation
+
+	// Query answers query requests. If nil, every sub-query gets a default (successful) answer.
+	Query func(query *api.RequestQuery) *api.ResponseQuery
+
+	// Action answers action requests. If nil, every action succeeds.
+	Action func(action *api.RequestAction) *api.ResponseAction
+
+	// EndLoop ends the game once the game loop reaches it (zero means never).
+	EndLoop uint32
+
+	// Results are reported in the observation once the game has ended.
+	Results []*api.PlayerResult
+
+	// Handle is called before the default handling of every request. If it returns a non-nil
+	// response that is sent instead (with Id and Status filled in if they are left empty).
+	Handle func(request *api.Request) *api.Response
+
+	server *httptest.Server
+
+	mu        sync.Mutex
+	status    api.Status
+	gameLoop  uint32
+	saveLoop  uint32
+	saved     bool
+	players   api.PlayerID
+	realtime  bool
+	requests  []*api.Request
+	conns     map[*websocket.Conn]bool
+	connCount int
+}
+
+// NewServer starts a fake SC2 instance listening on a random local port.
+func NewServer() *Server {
+	s := &Server{
+		Ping: api.ResponsePing{
+			GameVersion: "4.10.0.75689",
+			DataVersion: "B89B5D6FA7CBF6452E721311BFBC6CB2",
+			DataBuild:   75689,
+			BaseBuild:   75689,
+		},
+		GameInfo: &api.ResponseGameInfo{},
+		Data:     &api.ResponseData{},
+		status:   api.Status_launched,
+		conns:    map[*websocket.Conn]bool{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sc2api", s.serve)
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// Address returns the host clients should connect to.
+func (s *Server) Address() string {
+	host, _, _ := net.SplitHostPort(s.server.Listener.Addr().String())
+	return host
+}
+
+// Port returns the port clients should connect to.
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.server.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// Close shuts down the server and drops any open connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	for ws := range s.conns {
+		ws.Close()
+	}
+	s.mu.Unlock()
+
+	s.server.Close()
+}
+
+// Drop closes all currently open websocket connections without stopping the server.
+func (s *Server) Drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ws := range s.conns {
+		ws.Close()
+		delete(s.conns, ws)
+	}
+}
+
+// Connections returns the total number of websocket connections accepted so far.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connCount
+}
+
+// Status returns the current game status.
+func (s *Server) Status() api.Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// SetStatus overrides the current game status.
+func (s *Server) SetStatus(status api.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// GameLoop returns the current game loop.
+func (s *Server) GameLoop() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.gameLoop
+}
+
+// Requests returns all requests received so far, in order.
+func (s *Server) Requests() []*api.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]*api.Request, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024 * 1024,
+	WriteBufferSize: 1024 * 1024,
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	s.mu.Lock()
+	s.conns[ws] = true
+	s.connCount++
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, ws)
+		s.mu.Unlock()
+		ws.Close()
+	}()
+
+	for {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		req := &api.Request{}
+		if err := proto.Unmarshal(data, req); err != nil {
+			log.Print(err)
+			return
+		}
+
+		resp := s.handle(req)
+		if data, err = proto.Marshal(resp); err != nil {
+			log.Print(err)
+			return
+		}
+		if err := ws.WriteMessage(websocket.BinaryMessage, data); err != nil {
+			return
+		}
+
+		if resp.GetQuit() != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(req *api.Request) *api.Response {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	if s.Handle != nil {
+		if resp := s.Handle(req); resp != nil {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if resp.Id == 0 {
+				resp.Id = req.Id
+			}
+			if resp.Status == api.Status_nil {
+				resp.Status = s.status
+			}
+			return resp
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &api.Response{Id: req.Id}
+	if err := s.respond(req, resp); err != nil {
+		resp.Error = append(resp.Error, err.Error())
+	}
+	resp.Status = s.status
+	return resp
+}
+
+// respond fills in the response for the request and updates the game status (s.mu must be held).
+func (s *Server) respond(req *api.Request, resp *api.Response) error {
+	switch r := req.Request.(type) {
+	case *api.Request_Ping:
+		ping := s.Ping
+		resp.Response = &api.Response_Ping{Ping: &ping}
+
+	case *api.Request_CreateGame:
+		if err := s.expect("create_game", api.Status_launched); err != nil {
+			return err
+		}
+		result := &api.ResponseCreateGame{}
+		switch {
+		case r.CreateGame.GetMap() == nil:
+			result.Error = api.ResponseCreateGame_MissingMap
+		case len(r.CreateGame.GetPlayerSetup()) == 0:
+			result.Error = api.ResponseCreateGame_MissingPlayerSetup
+		default:
+			s.status = api.Status_init_game
+			s.realtime = r.CreateGame.GetRealtime()
+			s.gameLoop = 0
+			s.players = 0
+		}
+		resp.Response = &api.Response_CreateGame{CreateGame: result}
+
+	case *api.Request_JoinGame:
+		if err := s.expect("join_game", api.Status_launched, api.Status_init_game); err != nil {
+			return err
+		}
+		result := &api.ResponseJoinGame{}
+		switch {
+		case r.JoinGame.GetParticipation() == nil:
+			result.Error = api.ResponseJoinGame_MissingParticipation
+		case r.JoinGame.GetOptions() == nil:
+			result.Error = api.ResponseJoinGame_MissingOptions
+		default:
+			s.status = api.Status_in_game
+			s.players++
+			result.PlayerId = s.players
+		}
+		resp.Response = &api.Response_JoinGame{JoinGame: result}
+
+	case *api.Request_RestartGame:
+		if err := s.expect("restart_game", api.Status_in_game, api.Status_ended); err != nil {
+			return err
+		}
+		s.status = api.Status_in_game
+		s.gameLoop = 0
+		s.saved = false
+		resp.Response = &api.Response_RestartGame{RestartGame: &api.ResponseRestartGame{}}
+
+	case *api.Request_LeaveGame:
+		if err := s.expect("leave_game", api.Status_in_game, api.Status_ended); err != nil {
+			return err
+		}
+		s.status = api.Status_launched
+		resp.Response = &api.Response_LeaveGame{LeaveGame: &api.ResponseLeaveGame{}}
+
+	case *api.Request_QuickSave:
+		if err := s.expect("quick_save", api.Status_in_game); err != nil {
+			return err
+		}
+		s.saveLoop, s.saved = s.gameLoop, true
+		resp.Response = &api.Response_QuickSave{QuickSave: &api.ResponseQuickSave{}}
+
+	case *api.Request_QuickLoad:
+		if err := s.expect("quick_load", api.Status_in_game, api.Status_ended); err != nil {
+			return err
+		}
+		if !s.saved {
+			return fmt.Errorf("no quick save available")
+		}
+		s.status = api.Status_in_game
+		s.gameLoop = s.saveLoop
+		resp.Response = &api.Response_QuickLoad{QuickLoad: &api.ResponseQuickLoad{}}
+
+	case *api.Request_Quit:
+		s.status = api.Status_quit
+		resp.Response = &api.Response_Quit{Quit: &api.ResponseQuit{}}
+
+	case *api.Request_GameInfo:
+		if err := s.expect("game_info", api.Status_in_game, api.Status_in_replay, api.Status_ended); err != nil {
+			return err
+		}
+		info := proto.Clone(s.GameInfo).(*api.ResponseGameInfo)
+		resp.Response = &api.Response_GameInfo{GameInfo: info}
+
+	case *api.Request_Data:
+		if err := s.expect("data", api.Status_in_game, api.Status_in_replay, api.Status_ended); err != nil {
+			return err
+		}
+		data := proto.Clone(s.Data).(*api.ResponseData)
+		resp.Response = &api.Response_Data{Data: data}
+
+	case *api.Request_Observation:
+		if err := s.expect("observation", api.Status_in_game, api.Status_in_replay, api.Status_ended); err != nil {
+			return err
+		}
+		// In realtime mode time passes while waiting for the requested loop
+		if s.realtime && r.Observation.GetGameLoop() > s.gameLoop {
+			s.advance(r.Observation.GetGameLoop() - s.gameLoop)
+		}
+		resp.Response = &api.Response_Observation{Observation: s.observe()}
+
+	case *api.Request_Step:
+		if err := s.expect("step", api.Status_in_game); err != nil {
+			return err
+		}
+		if s.realtime {
+			return fmt.Errorf("step is not supported in realtime mode")
+		}
+		s.advance(r.Step.GetCount())
+		resp.Response = &api.Response_Step{Step: &api.ResponseStep{SimulationLoop: s.gameLoop}}
+
+	case *api.Request_Action:
+		if err := s.expect("action", api.Status_in_game); err != nil {
+			return err
+		}
+		var result *api.ResponseAction
+		if s.Action != nil {
+			result = s.Action(r.Action)
+		} else {
+			result = &api.ResponseAction{Result: make([]api.ActionResult, len(r.Action.GetActions()))}
+			for i := range result.Result {
+				result.Result[i] = api.ActionResult_Success
+			}
+		}
+		resp.Response = &api.Response_Action{Action: result}
+
+	case *api.Request_ObsAction:
+		if err := s.expect("obs_action", api.Status_in_replay); err != nil {
+			return err
+		}
+		resp.Response = &api.Response_ObsAction{ObsAction: &api.ResponseObserverAction{}}
+
+	case *api.Request_Query:
+		if err := s.expect("query", api.Status_in_game, api.Status_in_replay); err != nil {
+			return err
+		}
+		var result *api.ResponseQuery
+		if s.Query != nil {
+			result = s.Query(r.Query)
+		} else {
+			result = defaultQuery(r.Query)
+		}
+		resp.Response = &api.Response_Query{Query: result}
+
+	case *api.Request_Debug:
+		if err := s.expect("debug", api.Status_in_game); err != nil {
+			return err
+		}
+		resp.Response = &api.Response_Debug{Debug: &api.ResponseDebug{}}
+
+	case *api.Request_SaveReplay:
+		if err := s.expect("save_replay", api.Status_in_game, api.Status_ended); err != nil {
+			return err
+		}
+		resp.Response = &api.Response_SaveReplay{SaveReplay: &api.ResponseSaveReplay{}}
+
+	default:
+		return fmt.Errorf("unsupported request: %T", req.Request)
+	}
+	return nil
+}
+
+// expect returns an error unless the current status is one of the valid ones.
+func (s *Server) expect(name string, valid ...api.Status) error {
+	for _, status := range valid {
+		if s.status == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is not valid in status %v", name, s.status)
+}
+
+// advance moves the game loop forward, ending the game if EndLoop is reached.
+func (s *Server) advance(count uint32) {
+	s.gameLoop += count
+	if s.EndLoop > 0 && s.gameLoop >= s.EndLoop {
+		s.gameLoop = s.EndLoop
+		s.status = api.Status_ended
+	}
+}
+
+func (s *Server) observe() *api.ResponseObservation {
+	var obs *api.ResponseObservation
+	if s.Observe != nil {
+		obs = s.Observe(s.gameLoop)
+	}
+	if obs == nil {
+		obs = &api.ResponseObservation{}
+	}
+	if obs.Observation == nil {
+		obs.Observation = &api.Observation{}
+	}
+	obs.Observation.GameLoop = s.gameLoop
+
+	if s.status == api.Status_ended && len(obs.PlayerResult) == 0 {
+		obs.PlayerResult = s.Results
+	}
+	return obs
+}
+
+// defaultQuery returns one successful answer for every sub-query.
+func defaultQuery(query *api.RequestQuery) *api.ResponseQuery {
+	result := &api.ResponseQuery{
+		Pathing:    make([]*api.ResponseQueryPathing, len(query.GetPathing())),
+		Abilities:  make([]*api.ResponseQueryAvailableAbilities, len(query.GetAbilities())),
+		Placements: make([]*api.ResponseQueryBuildingPlacement, len(query.GetPlacements())),
+	}
+	for i, p := range query.GetPathing() {
+		dist := float32(0)
+		if start := p.GetStartPos(); start != nil && p.GetEndPos() != nil {
+			dist = start.Distance(*p.GetEndPos())
+		}
+		result.Pathing[i] = &api.ResponseQueryPathing{Distance: dist}
+	}
+	for i, a := range query.GetAbilities() {
+		result.Abilities[i] = &api.ResponseQueryAvailableAbilities{UnitTag: a.GetUnitTag()}
+	}
+	for i := range query.GetPlacements() {
+		result.Placements[i] = &api.ResponseQueryBuildingPlacement{Result: api.ActionResult_Success}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package sc2test_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/sc2test"
+)
+
+func TestClientGame(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.EndLoop = 100
+	s.Results = []*api.PlayerResult{{PlayerId: 1, Result: api.Result_Victory}}
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if c.Proto().BaseBuild != s.Ping.BaseBuild {
+		t.Errorf("ping: got base build %v, expected %v", c.Proto().BaseBuild, s.Ping.BaseBuild)
+	}
+
+	setup := client.NewParticipant(api.Race_Terran, nil, "test")
+	if err := c.CreateGame("test.SC2Map", []*api.PlayerSetup{setup.PlayerSetup}, false); err != nil {
+		t.Fatal(err)
+	}
+	if s.Status() != api.Status_init_game {
+		t.Fatalf("status after create: %v", s.Status())
+	}
+
+	if err := c.RequestJoinGame(setup.PlayerSetup, &api.InterfaceOptions{Raw: true}, client.Ports{}); err != nil {
+		t.Fatal(err)
+	}
+	if c.PlayerID() != 1 || !c.IsInGame() {
+		t.Fatalf("join: player %v, in game %v", c.PlayerID(), c.IsInGame())
+	}
+
+	if err := c.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := 0
+	for c.IsInGame() {
+		if err := c.Step(8); err != nil {
+			t.Fatal(err)
+		}
+		steps++
+	}
+	if steps != 13 {
+		t.Errorf("expected 13 steps, got %v", steps)
+	}
+	if got := c.Observation().GetPlayerResult(); len(got) != 1 || got[0].Result != api.Result_Victory {
+		t.Errorf("unexpected result: %v", got)
+	}
+
+	if err := c.RequestLeaveGame(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Status() != api.Status_launched {
+		t.Errorf("status after leave: %v", s.Status())
+	}
+}
+
+func TestInvalidStatus(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetObservation(); err == nil {
+		t.Error("expected an error observing before the game was created")
+	}
+}
+
+func TestScriptedResponses(t *testing.T) {
+	s := sc2test.NewServer()
+	defer s.Close()
+	s.Action = func(r *api.RequestAction) *api.ResponseAction {
+		return &api.ResponseAction{Result: []api.ActionResult{api.ActionResult_NotEnoughMinerals}}
+	}
+	s.SetStatus(api.Status_in_game)
+
+	c := &client.Client{}
+	if err := c.Connect(s.Address(), s.Port(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	results := c.SendActions([]*api.Action{{ActionChat: &api.ActionChat{Message: "hi"}}})
+	if len(results) != 1 || results[0] != api.ActionResult_NotEnoughMinerals {
+		t.Errorf("unexpected action results: %v", results)
+	}
+
+	requests := s.Requests()
+	if len(requests) != 2 || requests[1].GetAction().GetActions()[0].GetActionChat().GetMessage() != "hi" {
+		t.Errorf("unexpected requests: %v", requests)
+	}
+}
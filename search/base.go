@@ -1,7 +1,7 @@
 package search
 
 import (
-	"log"
+	"fmt"
 	"sort"
 	"strings"
 
@@ -70,7 +70,7 @@ func (base *Base) updateResource(u botutil.Unit) {
 	case u.HasVespene:
 		base.Geysers = base.updateOrAdd(base.Geysers, u)
 	default:
-		log.Panicf("unknown resource: %v", u)
+		panic(fmt.Sprintf("unknown resource: %v", u))
 	}
 
 	base.Resources[u.Tag] = u
@@ -157,7 +157,7 @@ func (base *Base) updateOrAdd(units []botutil.Unit, u botutil.Unit) []botutil.Un
 	for i, u2 := range units {
 		if u2.Pos2D().Distance2(u.Pos2D()) < 1 {
 			if u2.Pos2D() != u.Pos2D() {
-				log.Panicf("%v != %v", u2.Pos2D(), u.Pos2D())
+				panic(fmt.Sprintf("%v != %v", u2.Pos2D(), u.Pos2D()))
 			}
 
 			units[i] = u
@@ -193,8 +193,35 @@ func (base *Base) updateOrAdd(units []botutil.Unit, u botutil.Unit) []botutil.Un
 }
 
 func (base *Base) addWorker(worker botutil.Unit) {
+	base.Workers[worker.Tag] = worker
+	if !base.addGasWorker(worker) {
+		base.addMineralWorker(worker)
+	}
+}
+
+// addGasWorker assigns the worker to a finished gas building with less than 3 workers.
+func (base *Base) addGasWorker(worker botutil.Unit) bool {
+	workerTag := worker.Tag
+	for _, geyser := range base.Geysers {
+		building := base.GasBuildings[geyser.Pos2D()]
+		if building.IsNil() || building.Alliance != api.Alliance_Self || building.BuildProgress != 1.0 {
+			continue
+		}
+		if len(base.minedBy[geyser.Tag]) < 3 {
+			base.m.bot.Logger().Debug("Adding worker to gas", "worker", workerTag, "base", base.i, "geyser", geyser.Tag)
+			base.Workers[workerTag] = worker
+			base.minedBy[geyser.Tag][workerTag] = true
+			base.mining[workerTag] = geyser.Tag
+			return true
+		}
+	}
+	return false
+}
+
+// addMineralWorker assigns the worker to the closest mineral patch with room, preferring the
+// close patches and 2 workers per patch.
+func (base *Base) addMineralWorker(worker botutil.Unit) bool {
 	workerTag := worker.Tag
-	base.Workers[workerTag] = worker
 
 	closeMinerals := func(minerals []botutil.Unit, num int) bool {
 		distances := make([]struct {
@@ -210,7 +237,8 @@ func (base *Base) addWorker(worker botutil.Unit) {
 		})
 		for _, patch := range distances {
 			if len(base.minedBy[patch.tag]) < num {
-				log.Printf("adding to minerals worker: %v i: %v patchTag: %v", workerTag, base.i, patch.tag)
+				base.m.bot.Logger().Debug("Adding worker to minerals", "worker", workerTag, "base", base.i, "patch", patch.tag)
+				base.Workers[workerTag] = worker
 				base.minedBy[patch.tag][workerTag] = true
 				base.mining[workerTag] = patch.tag
 				return true
@@ -219,31 +247,14 @@ func (base *Base) addWorker(worker botutil.Unit) {
 		return false
 	}
 
-	for _, geyser := range base.Geysers {
-		building := base.GasBuildings[geyser.Pos2D()]
-		if building.IsNil() || building.Alliance != api.Alliance_Self || building.BuildProgress != 1.0 {
-			continue
-		}
-		if len(base.minedBy[geyser.Tag]) < 3 {
-			log.Printf("adding to gas worker: %v i: %v patchTag: %v", workerTag, base.i, geyser.Tag)
-			base.minedBy[geyser.Tag][workerTag] = true
-			base.mining[workerTag] = geyser.Tag
-			return
-		}
-	}
-
-	if closeMinerals(base.Minerals[0:4], 2) {
-		return
-	}
-	if closeMinerals(base.Minerals[4:], 2) {
-		return
-	}
-	if closeMinerals(base.Minerals[4:], 3) {
-		return
-	}
-	if closeMinerals(base.Minerals[0:4], 3) {
-		return
+	split := 4
+	if len(base.Minerals) < split {
+		split = len(base.Minerals)
 	}
+	return closeMinerals(base.Minerals[:split], 2) ||
+		closeMinerals(base.Minerals[split:], 2) ||
+		closeMinerals(base.Minerals[split:], 3) ||
+		closeMinerals(base.Minerals[:split], 3)
 }
 
 func (base *Base) GetWorker() botutil.Unit {
@@ -416,7 +427,7 @@ func (base *Base) RebalanceToGas() {
 			continue
 		}
 		for len(base.minedBy[geyser.Tag]) < 3 {
-			log.Printf("moving worker to gas -- current workers: %v\n", len(base.minedBy[geyser.Tag]))
+			base.m.bot.Logger().Debug("Moving worker to gas", "workers", len(base.minedBy[geyser.Tag]))
 			worker := base.GetWorker()
 			base.addWorker(worker)
 		}
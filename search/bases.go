@@ -1,8 +1,6 @@
 package search
 
 import (
-	"log"
-
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/botutil"
 	"github.com/chippydip/go-sc2ai/enums/ability"
@@ -13,6 +11,8 @@ type bases struct {
 	distances   []float32 // from i <-> j where i < j at index j*(j-1)/2 + i
 	cache       map[api.Point2D]*Base
 	usedWorkers map[api.UnitTag]bool
+	freeWorkers []botutil.Unit
+	manager     *WorkerManager
 }
 
 func (b *bases) distance(i, j int) float32 {
@@ -76,6 +76,7 @@ func (b *bases) update(bot *botutil.Bot) {
 	for _, base := range b.Bases {
 		base.update(bot)
 	}
+	b.freeWorkers = b.freeWorkers[:0]
 
 	bot.AllUnits().Each(func(u botutil.Unit) {
 		if u.IsTownHall() {
@@ -125,7 +126,7 @@ func (b *bases) update(bot *botutil.Bot) {
 				return
 			}
 
-			log.Printf("nothing to do with free worker tag: %v", u.Tag)
+			b.freeWorkers = append(b.freeWorkers, u)
 		}
 	})
 
@@ -162,16 +163,24 @@ func (b *bases) update(bot *botutil.Bot) {
 				}
 
 				// move worker from one base to another
-				log.Printf("moving over saturated worker: %v from base: %v to: %v travel: %v untilFinish: %v", worker.Tag, base.i, inConstruction.i, travelTime, untilFinish)
+				bot.Logger().Debug("Moving over saturated worker", "worker", worker.Tag, "from", base.i, "to", inConstruction.i, "travel", travelTime, "untilFinish", untilFinish)
 				worker = base.GetWorker()
 				inConstruction.addWorker(worker)
 			}
 		}
 	}
 
-	// rebalance gas
-	for _, base := range b.Bases {
-		base.RebalanceToGas()
+	if b.manager != nil {
+		b.manager.distribute(bot)
+	} else {
+		for _, worker := range b.freeWorkers {
+			bot.Logger().Debug("Nothing to do with free worker", "worker", worker.Tag)
+		}
+
+		// rebalance gas
+		for _, base := range b.Bases {
+			base.RebalanceToGas()
+		}
 	}
 
 	// step
@@ -1,8 +1,6 @@
 package search
 
 import (
-	"log"
-
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/botutil"
 	"github.com/chippydip/go-sc2ai/enums/ability"
@@ -58,6 +56,14 @@ func ShowDebugBoxes(bot *botutil.Bot) {
 	//debugBoxes = make([]*api.DebugBox, 0)
 }
 
+// DebugPrint updates the grid and draws the footprint of every known structure.
+func (pg *PlacementGrid) DebugPrint(bot *botutil.Bot) {
+	pg.Update()
+	debugBoxes = debugBoxes[:0]
+	pg.DebugBuildings()
+	ShowDebugBoxes(bot)
+}
+
 func (pg *PlacementGrid) DebugBuildings() {
 	heightMap := NewHeightMap(pg.bot.GameInfo().StartRaw)
 	for _, v := range pg.structures {
@@ -70,7 +76,7 @@ func (pg *PlacementGrid) DebugBuildings() {
 }
 
 func (pg *PlacementGrid) DebugLocationsNearPoint(pos api.Point2D, distance int32) {
-	log.Printf("pos: %v", pos)
+	pg.bot.Logger().Debug("Debugging locations near point", "pos", pos)
 	heightMap := NewHeightMap(pg.bot.GameInfo().StartRaw)
 	xMin, yMin := int32(pos.X-float32(distance)/2), int32(pos.Y-float32(distance)/2)
 	xMax, yMax := xMin+distance, yMin+distance
@@ -117,7 +123,7 @@ func (pg *PlacementGrid) DebugLocationsNearPoint(pos api.Point2D, distance int32
 				Min:   &api.Point{X: X, Y: Y, Z: z},
 				Max:   &api.Point{X: Y + 1, Y: Y + 1, Z: z},
 			})
-			log.Printf("Wrong! x: %v y: %v height: %v width: %v\n", X, Y, pg.grid.Height(), pg.grid.Width())
+			pg.bot.Logger().Warn("Wrong placement", "x", X, "y", Y, "height", pg.grid.Height(), "width", pg.grid.Width())
 		} else {
 		}
 	}
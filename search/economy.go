@@ -0,0 +1,210 @@
+package search
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// BaseSaturation describes the workers assigned to one of our bases.
+type BaseSaturation struct {
+	Base           int     `json:"base"`
+	MineralWorkers int     `json:"mineral_workers"`
+	GasWorkers     int     `json:"gas_workers"`
+	Ideal          int     `json:"ideal"` // 2 per mineral patch and 3 per finished gas building
+	MineralRate    float32 `json:"mineral_rate"`
+	VespeneRate    float32 `json:"vespene_rate"`
+}
+
+// EconomySample is a snapshot of economy statistics. Rates are per game minute.
+type EconomySample struct {
+	GameLoop           uint32           `json:"game_loop"`
+	MineralRate        float32          `json:"mineral_rate"`
+	VespeneRate        float32          `json:"vespene_rate"`
+	CollectedMinerals  float32          `json:"collected_minerals"`
+	CollectedVespene   float32          `json:"collected_vespene"`
+	UnspentMinerals    float32          `json:"unspent_minerals"` // average since the previous sample
+	UnspentVespene     float32          `json:"unspent_vespene"`  // average since the previous sample
+	IdleProductionTime float32          `json:"idle_production_time"`
+	IdleWorkerTime     float32          `json:"idle_worker_time"`
+	SupplyBlockedLoops float32          `json:"supply_blocked_loops"` // total game loops spent supply blocked
+	MaxedOutLoops      float32          `json:"maxed_out_loops"`      // total game loops spent at 200 supply
+	Workers            int              `json:"workers"`
+	Bases              []BaseSaturation `json:"bases,omitempty"`
+}
+
+// Economy tracks income, saturation and spending efficiency over the course of a game. Samples
+// are taken every interval game loops and use the bases' worker assignments as of the last
+// Map.Update.
+type Economy struct {
+	m        *Map
+	interval uint32
+	samples  []EconomySample
+
+	lastLoop     uint32
+	nextSample   uint32
+	weight       uint64
+	mineralsSum  uint64
+	vespeneSum   uint64
+	supply       supplyState
+	blockedLoops float32
+	maxedLoops   float32
+}
+
+// supplyState is whether production is held up by supply at the time of an observation.
+type supplyState int
+
+const (
+	supplyFree supplyState = iota
+	supplyBlocked
+	supplyMaxed
+)
+
+func newSupplyState(pc *api.PlayerCommon) supplyState {
+	switch {
+	case pc.GetFoodCap() == 0, pc.GetFoodUsed() < pc.GetFoodCap():
+		return supplyFree // no player data yet or room to build
+	case pc.GetFoodUsed() >= 200:
+		return supplyMaxed // can't build more supply, so this isn't a block
+	default:
+		return supplyBlocked
+	}
+}
+
+// supplyTime returns how much of the time between two observations was spent in the given state.
+// We only see the state at each observation, so when it changed the change is assumed to have
+// happened halfway between them.
+func supplyTime(prev, cur, state supplyState, dt uint32) float32 {
+	t := float32(0)
+	if prev == state {
+		t += float32(dt) / 2
+	}
+	if cur == state {
+		t += float32(dt) / 2
+	}
+	return t
+}
+
+// NewEconomy creates a new economy tracker and registers it to update after each step.
+func NewEconomy(m *Map, interval uint32) *Economy {
+	if interval == 0 {
+		interval = 224 // 10 seconds
+	}
+	e := &Economy{m: m, interval: interval}
+	obs := m.bot.Observation().GetObservation()
+	e.lastLoop = obs.GetGameLoop()
+	e.supply = newSupplyState(obs.GetPlayerCommon())
+	e.nextSample = e.lastLoop + interval
+	m.bot.OnAfterStep(e.update)
+	return e
+}
+
+func (e *Economy) update() {
+	obs := e.m.bot.Observation().GetObservation()
+	loop := obs.GetGameLoop()
+	if loop <= e.lastLoop {
+		return // restarted or no time passed
+	}
+	dt := loop - e.lastLoop
+	e.lastLoop = loop
+
+	// Use the raw values since Player has pending spending and reservations applied
+	pc := obs.GetPlayerCommon()
+	e.weight += uint64(dt)
+	e.mineralsSum += uint64(pc.GetMinerals()) * uint64(dt)
+	e.vespeneSum += uint64(pc.GetVespene()) * uint64(dt)
+	supply := newSupplyState(pc)
+	e.blockedLoops += supplyTime(e.supply, supply, supplyBlocked, dt)
+	e.maxedLoops += supplyTime(e.supply, supply, supplyMaxed, dt)
+	e.supply = supply
+
+	if loop >= e.nextSample {
+		e.samples = append(e.samples, e.Current())
+		e.weight, e.mineralsSum, e.vespeneSum = 0, 0, 0
+		e.nextSample = loop + e.interval
+	}
+}
+
+// Current returns statistics for the current game loop.
+func (e *Economy) Current() EconomySample {
+	obs := e.m.bot.Observation().GetObservation()
+	score := obs.GetScore().GetScoreDetails()
+
+	sample := EconomySample{
+		GameLoop:           obs.GetGameLoop(),
+		MineralRate:        score.GetCollectionRateMinerals(),
+		VespeneRate:        score.GetCollectionRateVespene(),
+		CollectedMinerals:  score.GetCollectedMinerals(),
+		CollectedVespene:   score.GetCollectedVespene(),
+		IdleProductionTime: score.GetIdleProductionTime(),
+		IdleWorkerTime:     score.GetIdleWorkerTime(),
+		SupplyBlockedLoops: e.blockedLoops,
+		MaxedOutLoops:      e.maxedLoops,
+		Bases:              e.Saturation(),
+	}
+	if e.weight > 0 {
+		sample.UnspentMinerals = float32(e.mineralsSum) / float32(e.weight)
+		sample.UnspentVespene = float32(e.vespeneSum) / float32(e.weight)
+	} else {
+		sample.UnspentMinerals = float32(obs.GetPlayerCommon().GetMinerals())
+		sample.UnspentVespene = float32(obs.GetPlayerCommon().GetVespene())
+	}
+	for _, s := range sample.Bases {
+		sample.Workers += s.MineralWorkers + s.GasWorkers
+	}
+	return sample
+}
+
+// Saturation returns the worker assignments of each of our bases. The collection rates from the
+// score are split between bases in proportion to their mineral and gas workers.
+func (e *Economy) Saturation() []BaseSaturation {
+	var sat []BaseSaturation
+	mineralWorkers, gasWorkers := 0, 0
+	for _, base := range e.m.Bases {
+		if !base.IsSelfOwned() {
+			continue
+		}
+		s := BaseSaturation{
+			Base:  base.i,
+			Ideal: len(base.Minerals)*2 + base.NumGasGeysers()*3,
+		}
+		for _, resource := range base.mining {
+			if base.Resources[resource].HasVespene {
+				s.GasWorkers++
+			} else {
+				s.MineralWorkers++
+			}
+		}
+		mineralWorkers += s.MineralWorkers
+		gasWorkers += s.GasWorkers
+		sat = append(sat, s)
+	}
+
+	score := e.m.bot.Observation().GetObservation().GetScore().GetScoreDetails()
+	for i := range sat {
+		if mineralWorkers > 0 {
+			sat[i].MineralRate = score.GetCollectionRateMinerals() * float32(sat[i].MineralWorkers) / float32(mineralWorkers)
+		}
+		if gasWorkers > 0 {
+			sat[i].VespeneRate = score.GetCollectionRateVespene() * float32(sat[i].GasWorkers) / float32(gasWorkers)
+		}
+	}
+	return sat
+}
+
+// Samples returns the time series of samples taken so far.
+func (e *Economy) Samples() []EconomySample {
+	return e.samples
+}
+
+// WriteJSON writes the samples to w as JSON lines so runs can be compared between bot versions.
+func (e *Economy) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, s := range e.samples {
+		if err := enc.Encode(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+)
+
+func TestEconomy(t *testing.T) {
+	w, locs := newTestWorld(2)
+	for i, loc := range locs {
+		w.add(terran.CommandCenter, api.Alliance_Self, loc.Location.X, loc.Location.Y)
+		for j := 0; j < 3+i; j++ {
+			w.add(terran.SCV, api.Alliance_Self, loc.Location.X, loc.Location.Y+1)
+		}
+	}
+	w.add(terran.Refinery, api.Alliance_Self, locs[1].Location.X+7, locs[1].Location.Y)
+
+	score := &api.Score{ScoreDetails: &api.ScoreDetails{CollectionRateMinerals: 600, CollectionRateVespene: 120}}
+	observe := func(loop, minerals, food uint32) *api.Observation {
+		return &api.Observation{GameLoop: loop, Score: score,
+			PlayerCommon: &api.PlayerCommon{Minerals: minerals, FoodUsed: food, FoodCap: 15}}
+	}
+	w.start(locs, observe(0, 100, 10))
+	w.step(observe(0, 100, 10))
+	e := NewEconomy(w.m, 10)
+
+	w.step(observe(5, 200, 15))  // supply blocked somewhere in the last 5 loops
+	w.step(observe(10, 300, 15)) // still blocked, take the first sample
+	w.step(observe(15, 300, 10)) // unblocked
+	if n := len(e.Samples()); n != 1 {
+		t.Fatalf("got %v samples, expected 1", n)
+	}
+	s := e.Samples()[0]
+	if s.GameLoop != 10 || s.UnspentMinerals != 250 {
+		t.Errorf("got loop %v and %v unspent minerals, expected 10 and 250", s.GameLoop, s.UnspentMinerals)
+	}
+	if s.SupplyBlockedLoops != 7.5 || s.MaxedOutLoops != 0 {
+		t.Errorf("got %v blocked and %v maxed loops, expected 7.5 and 0", s.SupplyBlockedLoops, s.MaxedOutLoops)
+	}
+
+	// Being maxed out isn't a supply block
+	max := observe(20, 500, 200)
+	max.PlayerCommon.FoodCap = 200
+	w.step(max)
+	if s := e.Samples()[1]; s.SupplyBlockedLoops != 10 || s.MaxedOutLoops != 2.5 || s.UnspentMinerals != 400 {
+		t.Errorf("got %+v", s)
+	}
+
+	// 3 mineral workers at the first base, 1 mineral and 3 gas workers at the second
+	sat := e.Saturation()
+	if len(sat) != 2 {
+		t.Fatalf("got %v bases, expected 2", len(sat))
+	}
+	if sat[0].MineralWorkers != 3 || sat[0].GasWorkers != 0 || sat[0].Ideal != 16 {
+		t.Errorf("got first base %+v", sat[0])
+	}
+	if sat[1].MineralWorkers != 1 || sat[1].GasWorkers != 3 || sat[1].Ideal != 19 {
+		t.Errorf("got second base %+v", sat[1])
+	}
+	if sat[0].MineralRate != 450 || sat[1].MineralRate != 150 || sat[0].VespeneRate != 0 || sat[1].VespeneRate != 120 {
+		t.Errorf("got rates %+v and %+v", sat[0], sat[1])
+	}
+
+	var buf bytes.Buffer
+	if err := e.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var lines []EconomySample
+	for scanner := bufio.NewScanner(&buf); scanner.Scan(); {
+		var sample EconomySample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, sample)
+	}
+	if len(lines) != 2 || lines[1].GameLoop != 20 || lines[1].Workers != 7 || len(lines[1].Bases) != 2 {
+		t.Errorf("got %+v", lines)
+	}
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"log/slog"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/client"
+	"github.com/chippydip/go-sc2ai/enums/neutral"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+)
+
+// testData has the unit types needed to build bases.
+var testData = func() *api.ResponseData {
+	units := make([]*api.UnitTypeData, 2000)
+	units[terran.SCV] = &api.UnitTypeData{UnitId: terran.SCV, MovementSpeed: 2.8125,
+		Weapons: []*api.Weapon{{Type: api.Weapon_Ground, Damage: 5, Attacks: 1, Range: 0.1, Speed: 1.07}}}
+	units[terran.CommandCenter] = &api.UnitTypeData{UnitId: terran.CommandCenter, BuildTime: 1590,
+		Attributes: []api.Attribute{api.Attribute_Structure}}
+	units[terran.Refinery] = &api.UnitTypeData{UnitId: terran.Refinery, Attributes: []api.Attribute{api.Attribute_Structure}}
+	units[neutral.MineralField] = &api.UnitTypeData{UnitId: neutral.MineralField, Name: "MineralField", HasMinerals: true,
+		Attributes: []api.Attribute{api.Attribute_Structure}}
+	units[neutral.VespeneGeyser] = &api.UnitTypeData{UnitId: neutral.VespeneGeyser, Name: "VespeneGeyser", HasVespene: true,
+		Attributes: []api.Attribute{api.Attribute_Structure}}
+	return &api.ResponseData{Units: units}
+}()
+
+// fakeInfo is an AgentInfo that replays observations built by the tests. Anything the tests
+// don't need panics through the nil embedded interface.
+type fakeInfo struct {
+	client.AgentInfo
+	obs         *api.ResponseObservation
+	observation []func()
+	afterStep   []func()
+}
+
+func (i *fakeInfo) Data() *api.ResponseData               { return testData }
+func (i *fakeInfo) GameInfo() *api.ResponseGameInfo       { return &api.ResponseGameInfo{} }
+func (i *fakeInfo) Observation() *api.ResponseObservation { return i.obs }
+func (i *fakeInfo) Upgrades() []api.UpgradeID             { return nil }
+func (i *fakeInfo) HasUpgrade(api.UpgradeID) bool         { return false }
+func (i *fakeInfo) OnObservation(f func())                { i.observation = append(i.observation, f) }
+func (i *fakeInfo) OnAfterStep(f func())                  { i.afterStep = append(i.afterStep, f) }
+func (i *fakeInfo) OnBeforeStep(func())                   {}
+func (i *fakeInfo) Logger() *slog.Logger                  { return slog.Default() }
+func (i *fakeInfo) IsInGame() bool                        { return true }
+func (i *fakeInfo) IsRealtime() bool                      { return false }
+
+func (i *fakeInfo) Query(query api.RequestQuery) *api.ResponseQuery {
+	r := &api.ResponseQuery{}
+	for _, q := range query.Abilities {
+		r.Abilities = append(r.Abilities, &api.ResponseQueryAvailableAbilities{UnitTag: q.UnitTag})
+	}
+	return r
+}
+
+func (i *fakeInfo) SendActions(actions []*api.Action) []api.ActionResult {
+	results := make([]api.ActionResult, len(actions))
+	for j := range results {
+		results[j] = api.ActionResult_Success
+	}
+	return results
+}
+
+// step replaces the observation and runs the callbacks.
+func (i *fakeInfo) step(obs *api.Observation, units ...*api.Unit) {
+	obs.RawData = &api.ObservationRaw{Units: units, Event: &api.Event{}}
+	i.obs = &api.ResponseObservation{Observation: obs}
+	for _, f := range i.observation {
+		f()
+	}
+	for _, f := range i.afterStep {
+		f()
+	}
+}
+
+// testWorld builds the units for bases with 8 mineral patches and a geyser each, 20 apart.
+type testWorld struct {
+	info  *fakeInfo
+	bot   *botutil.Bot
+	m     *Map
+	tag   api.UnitTag
+	units []*api.Unit
+}
+
+func (w *testWorld) add(unitType api.UnitTypeID, alliance api.Alliance, x, y float32) *api.Unit {
+	w.tag++
+	u := &api.Unit{Tag: w.tag, UnitType: unitType, Alliance: alliance, Pos: &api.Point{X: x, Y: y},
+		BuildProgress: 1, DisplayType: api.DisplayType_Visible}
+	switch unitType {
+	case neutral.MineralField:
+		u.MineralContents = 1800
+	case neutral.VespeneGeyser:
+		u.VespeneContents = 2250
+	}
+	w.units = append(w.units, u)
+	return u
+}
+
+// newTestWorld creates a map with the given number of bases. Town halls, gas buildings and
+// workers are added by the tests before calling start.
+func newTestWorld(numBases int) (*testWorld, []BaseLocation) {
+	w := &testWorld{info: &fakeInfo{}}
+	var locs []BaseLocation
+	for i := 0; i < numBases; i++ {
+		loc := BaseLocation{Location: api.Point2D{X: float32(20*i) + 10, Y: 10}}
+		for j := 0; j < 8; j++ {
+			w.add(neutral.MineralField, api.Alliance_Neutral, loc.Location.X-3.5+float32(j), loc.Location.Y+6)
+		}
+		w.add(neutral.VespeneGeyser, api.Alliance_Neutral, loc.Location.X+7, loc.Location.Y)
+		locs = append(locs, loc)
+	}
+	return w, locs
+}
+
+// start creates the bot and map from the current units.
+func (w *testWorld) start(locs []BaseLocation, obs *api.Observation) {
+	w.info.step(obs, w.units...)
+	w.bot = botutil.NewBot(w.info)
+	w.m = &Map{bot: w.bot, bases: bases{cache: map[api.Point2D]*Base{}, usedWorkers: map[api.UnitTag]bool{}}}
+	for i, loc := range locs {
+		w.m.Bases = append(w.m.Bases, newBase(w.m, i, loc))
+	}
+	w.m.distances = make([]float32, len(locs)*(len(locs)-1)/2)
+}
+
+// step observes the units and updates the map.
+func (w *testWorld) step(obs *api.Observation) {
+	w.info.step(obs, w.units...)
+	w.m.bases.update(w.bot)
+}
@@ -1,7 +1,7 @@
 package search
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/botutil"
@@ -76,7 +76,7 @@ func computeDepth(bot *botutil.Bot) (api.ImageDataBytes, byte) {
 	}
 
 	if len(processed) != int(depth.Width()*depth.Height()) {
-		log.Panicf("Only process %v of %v cells", len(processed), depth.Width()*depth.Height())
+		panic(fmt.Sprintf("Only process %v of %v cells", len(processed), depth.Width()*depth.Height()))
 	}
 
 	return depth, min
@@ -1,7 +1,7 @@
 package search
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/chippydip/go-sc2ai/api"
 	"github.com/chippydip/go-sc2ai/botutil"
@@ -55,7 +55,7 @@ func UnitPlacementSize(u botutil.Unit) api.Size2DI {
 	// Cache and return the computed size
 	size := api.Size2DI{X: xMax - xMin, Y: yMax - yMin}
 	sizeCache[u.UnitType] = size
-	log.Printf("%v %v %v -> %v", unit.String(u.UnitType), u.Pos2D(), u.Radius, size)
+	slog.Debug("Computed placement size", "unit", unit.String(u.UnitType), "pos", u.Pos2D(), "radius", u.Radius, "size", size)
 	return size
 }
 
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"errors"
+	"image"
+	"image/color"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+)
+
+// Colors used when writing grid PNGs.
+var (
+	pngBlocked = color.RGBA{32, 32, 32, 255}
+	pngOpen    = color.RGBA{224, 224, 224, 255}
+)
+
+// writeGridPNG scales and flips a world grid so north is up and writes it to path.
+func writeGridPNG(path string, img image.Image, scale int) error {
+	return api.WritePNG(path, api.ScaleImage(api.FlipImage(img), scale))
+}
+
+// WritePathingPNG writes the starting pathing grid to path, scaled up by scale.
+func WritePathingPNG(bot *botutil.Bot, path string, scale int) error {
+	grid := bot.GameInfo().GetStartRaw().GetPathingGrid().Bits()
+	return writeGridPNG(path, grid.ToImage(pngBlocked, pngOpen), scale)
+}
+
+// WritePlacementPNG writes the starting placement grid to path, scaled up by scale.
+func WritePlacementPNG(bot *botutil.Bot, path string, scale int) error {
+	grid := bot.GameInfo().GetStartRaw().GetPlacementGrid().Bits()
+	return writeGridPNG(path, grid.ToImage(pngBlocked, pngOpen), scale)
+}
+
+// WriteTerrainHeightPNG writes the terrain height to path as a heat map, scaled up by scale.
+func WriteTerrainHeightPNG(bot *botutil.Bot, path string, scale int) error {
+	grid := bot.GameInfo().GetStartRaw().GetTerrainHeight().Bytes()
+	return writeGridPNG(path, grid.ToImage(api.HeatColormap), scale)
+}
+
+// WriteOpennessPNG writes the output of ComputeOpenness to path as a heat map, scaled up by scale.
+func WriteOpennessPNG(bot *botutil.Bot, path string, scale int) error {
+	return writeGridPNG(path, ComputeOpenness(bot).ToImage(api.HeatColormap), scale)
+}
+
+// WriteRenderPNG writes the RGB map render from the current observation to path, scaled up by
+// scale. It requires render options to be set in the interface options.
+func WriteRenderPNG(bot *botutil.Bot, path string, scale int) error {
+	render := bot.Observation().GetObservation().GetRenderData().GetMap()
+	if render == nil {
+		return errors.New("no map render in the observation (set InterfaceOptions.Render)")
+	}
+	return api.WritePNG(path, api.ScaleImage(render.ToImage(), scale))
+}
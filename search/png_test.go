@@ -0,0 +1,46 @@
+package search
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+func TestWriteGridPNG(t *testing.T) {
+	// Only the bottom left (south west) cell is open
+	grid := api.NewImageDataBits(2, 2)
+	grid.Set(0, 0, true)
+
+	path := filepath.Join(t.TempDir(), "grid.png")
+	if err := writeGridPNG(path, grid.ToImage(pngBlocked, pngOpen), 2); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b := img.Bounds(); b.Dx() != 4 || b.Dy() != 4 {
+		t.Fatalf("got bounds %v, expected 4x4", b)
+	}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			expected := pngBlocked
+			if x < 2 && y >= 2 {
+				expected = pngOpen // north is up
+			}
+			r, g, b, _ := img.At(x, y).RGBA()
+			if uint8(r>>8) != expected.R || uint8(g>>8) != expected.G || uint8(b>>8) != expected.B {
+				t.Errorf("got %v at (%v, %v), expected %v", img.At(x, y), x, y, expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package search
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+)
+
+// ThreatMap holds the damage per second enemies can do to ground and air units in each cell of
+// the pathing grid. It is rebuilt after each step from visible and remembered enemy units.
+// Remembered units that can move count for less the longer they have been out of sight.
+type ThreatMap struct {
+	bot      *botutil.Bot
+	pathable api.ImageDataBits
+	w, h     int32
+	ground   []float32
+	air      []float32
+
+	Margin float32 // distance outside weapon range over which the threat fades out (default 2)
+	Weight float32 // extra path cost per point of DPS in a cell (default 1)
+	Expire uint32  // game loops over which unseen mobile units fade out, 0 to never fade (default 224)
+}
+
+// NewThreatMap creates a threat map sized to the pathing grid and registers it to update after
+// each step.
+func NewThreatMap(bot *botutil.Bot) *ThreatMap {
+	pathable := pathingBits(bot.GameInfo().GetStartRaw().GetPathingGrid())
+	w, h := pathable.Width(), pathable.Height()
+	tm := &ThreatMap{
+		bot:      bot,
+		pathable: pathable,
+		w:        w,
+		h:        h,
+		ground:   make([]float32, w*h),
+		air:      make([]float32, w*h),
+		Margin:   2,
+		Weight:   1,
+		Expire:   224,
+	}
+	tm.Update()
+	bot.OnAfterStep(tm.Update)
+	return tm
+}
+
+// Update rebuilds the map from the enemies currently known to EnemyMemory.
+func (tm *ThreatMap) Update() {
+	for i := range tm.ground {
+		tm.ground[i], tm.air[i] = 0, 0
+	}
+	gameLoop := tm.bot.GameLoop
+	tm.bot.RememberedEnemies().Each(func(u botutil.Unit) {
+		if u.IsStructure() && !u.IsBuilt() {
+			return // under construction
+		}
+		weight := float32(1)
+		if !u.IsStructure() {
+			if lastSeen, ok := tm.bot.LastSeen(u.Tag); ok && lastSeen < gameLoop {
+				weight = tm.memoryWeight(gameLoop - lastSeen)
+			}
+		}
+		if weight <= 0 {
+			return
+		}
+		if dps := u.GroundDPS() * weight; dps > 0 {
+			tm.add(tm.ground, u.Pos2D(), u.GroundWeaponRange()+u.Radius, dps)
+		}
+		if dps := u.AirDPS() * weight; dps > 0 {
+			tm.add(tm.air, u.Pos2D(), u.AirWeaponRange()+u.Radius, dps)
+		}
+	})
+}
+
+// memoryWeight returns how much a mobile unit last seen age game loops ago still counts.
+func (tm *ThreatMap) memoryWeight(age uint32) float32 {
+	if tm.Expire == 0 {
+		return 1
+	}
+	if age >= tm.Expire {
+		return 0
+	}
+	return 1 - float32(age)/float32(tm.Expire)
+}
+
+// pathingBits decodes the pathing grid, which may be sent with one or eight bits per pixel.
+// Unknown formats result in an empty grid.
+func pathingBits(img *api.ImageData) api.ImageDataBits {
+	switch {
+	case img.GetSize_() == nil:
+		return api.ImageDataBits{}
+	case img.BitsPerPixel == 1:
+		return img.Bits()
+	case img.BitsPerPixel == 8:
+		w, h := img.Size_.X, img.Size_.Y
+		bytes, bits := img.Bytes(), api.NewImageDataBits(w, h)
+		for y := int32(0); y < h; y++ {
+			for x := int32(0); x < w; x++ {
+				bits.Set(x, y, bytes.Get(x, y) != 0)
+			}
+		}
+		return bits
+	}
+	return api.ImageDataBits{}
+}
+
+// add spreads dps over every cell within reach of pos, fading out over Margin beyond reach.
+func (tm *ThreatMap) add(grid []float32, pos api.Point2D, reach, dps float32) {
+	outer := reach + tm.Margin
+	xMin, xMax := int32(pos.X-outer), int32(pos.X+outer)
+	yMin, yMax := int32(pos.Y-outer), int32(pos.Y+outer)
+	for y := yMin; y <= yMax; y++ {
+		for x := xMin; x <= xMax; x++ {
+			if !tm.inBounds(x, y) {
+				continue
+			}
+			d := pos.Distance(cellCenter(x, y))
+			switch {
+			case d <= reach:
+				grid[y*tm.w+x] += dps
+			case d < outer:
+				grid[y*tm.w+x] += dps * (outer - d) / tm.Margin
+			}
+		}
+	}
+}
+
+func (tm *ThreatMap) inBounds(x, y int32) bool {
+	return x >= 0 && y >= 0 && x < tm.w && y < tm.h
+}
+
+func cellCenter(x, y int32) api.Point2D {
+	return api.Point2D{X: float32(x) + 0.5, Y: float32(y) + 0.5}
+}
+
+// Threat returns the enemy DPS against ground or air units at pos.
+func (tm *ThreatMap) Threat(pos api.Point2D, flying bool) float32 {
+	x, y := int32(pos.X), int32(pos.Y)
+	if !tm.inBounds(x, y) {
+		return 0
+	}
+	return tm.grid(flying)[y*tm.w+x]
+}
+
+// IsThreatened returns true if any known enemy can hit a ground or air unit at pos or is within
+// Margin of being able to.
+func (tm *ThreatMap) IsThreatened(pos api.Point2D, flying bool) bool {
+	return tm.Threat(pos, flying) > 0
+}
+
+func (tm *ThreatMap) grid(flying bool) []float32 {
+	if flying {
+		return tm.air
+	}
+	return tm.ground
+}
+
+// canEnter returns true if a unit can stand in the cell.
+func (tm *ThreatMap) canEnter(x, y int32, flying bool) bool {
+	return tm.inBounds(x, y) && (flying || tm.pathable.Get(x, y))
+}
+
+// SafestPoint returns the point within radius of pos with the least threat. Ties are broken in
+// favor of points closer to pos. Ground units only consider pathable cells.
+func (tm *ThreatMap) SafestPoint(pos api.Point2D, radius float32, flying bool) api.Point2D {
+	grid := tm.grid(flying)
+	best, bestThreat, bestDist := pos, float32(math.Inf(1)), float32(math.Inf(1))
+	xMin, xMax := int32(pos.X-radius), int32(pos.X+radius)
+	yMin, yMax := int32(pos.Y-radius), int32(pos.Y+radius)
+	for y := yMin; y <= yMax; y++ {
+		for x := xMin; x <= xMax; x++ {
+			if !tm.canEnter(x, y, flying) {
+				continue
+			}
+			p := cellCenter(x, y)
+			d := pos.Distance(p)
+			if d > radius {
+				continue
+			}
+			if t := grid[y*tm.w+x]; t < bestThreat || (t == bestThreat && d < bestDist) {
+				best, bestThreat, bestDist = p, t, d
+			}
+		}
+	}
+	return best
+}
+
+// SafestPathCost returns the cost of the safest path from one point to another. Each step costs
+// its length times one plus Weight for each point of DPS in the cell, so the result equals the
+// walking distance if the path is not threatened at all. It returns false if there is no path.
+func (tm *ThreatMap) SafestPathCost(from, to api.Point2D, flying bool) (float32, bool) {
+	sx, sy := int32(from.X), int32(from.Y)
+	tx, ty := int32(to.X), int32(to.Y)
+	if !tm.inBounds(sx, sy) || !tm.canEnter(tx, ty, flying) {
+		return 0, false
+	}
+
+	grid := tm.grid(flying)
+	cost := make([]float32, len(grid))
+	for i := range cost {
+		cost[i] = float32(math.Inf(1))
+	}
+	start, goal := sy*tm.w+sx, ty*tm.w+tx
+	cost[start] = 0
+
+	open := &pathQueue{{start, 0}}
+	for open.Len() > 0 {
+		curr := heap.Pop(open).(pathNode)
+		if curr.cost > cost[curr.i] {
+			continue // stale entry
+		}
+		if curr.i == goal {
+			return curr.cost, true
+		}
+
+		x, y := curr.i%tm.w, curr.i/tm.w
+		for _, n := range neighbors8 {
+			nx, ny := x+n.dx, y+n.dy
+			if !tm.canEnter(nx, ny, flying) {
+				continue
+			}
+			if n.dx != 0 && n.dy != 0 && (!tm.canEnter(x+n.dx, y, flying) || !tm.canEnter(x, y+n.dy, flying)) {
+				continue // don't cut corners
+			}
+			i := ny*tm.w + nx
+			if c := curr.cost + n.dist*(1+tm.Weight*grid[i]); c < cost[i] {
+				cost[i] = c
+				heap.Push(open, pathNode{i, c})
+			}
+		}
+	}
+	return 0, false
+}
+
+var neighbors8 = []struct {
+	dx, dy int32
+	dist   float32
+}{
+	{1, 0, 1}, {-1, 0, 1}, {0, 1, 1}, {0, -1, 1},
+	{1, 1, math.Sqrt2}, {1, -1, math.Sqrt2}, {-1, 1, math.Sqrt2}, {-1, -1, math.Sqrt2},
+}
+
+type pathNode struct {
+	i    int32
+	cost float32
+}
+
+// pathQueue is a min-heap of nodes ordered by cost.
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int            { return len(q) }
+func (q pathQueue) Less(i, j int) bool  { return q[i].cost < q[j].cost }
+func (q pathQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x interface{}) { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
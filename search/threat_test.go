@@ -0,0 +1,126 @@
+package search
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+)
+
+// newTestThreatMap returns a w x h threat map where every cell is pathable.
+func newTestThreatMap(w, h int32) *ThreatMap {
+	pathable := api.NewImageDataBits(w, h)
+	for y := int32(0); y < h; y++ {
+		for x := int32(0); x < w; x++ {
+			pathable.Set(x, y, true)
+		}
+	}
+	return &ThreatMap{pathable: pathable, w: w, h: h,
+		ground: make([]float32, w*h), air: make([]float32, w*h), Margin: 2, Weight: 1, Expire: 224}
+}
+
+func TestThreatMapAdd(t *testing.T) {
+	tm := newTestThreatMap(16, 16)
+	tm.add(tm.ground, api.Point2D{X: 5.5, Y: 5.5}, 2, 10)
+
+	expect := func(x, y, threat float32) {
+		t.Helper()
+		if got := tm.Threat(api.Point2D{X: x, Y: y}, false); got != threat {
+			t.Errorf("threat at (%v, %v): got %v, expected %v", x, y, got, threat)
+		}
+	}
+	expect(5.5, 5.5, 10)
+	expect(7.5, 5.5, 10) // at reach
+	expect(8.2, 5.7, 5)  // halfway through the fade margin
+	expect(9.5, 5.5, 0)  // reach plus margin
+	expect(-1, 5, 0)     // out of bounds
+
+	if !tm.IsThreatened(api.Point2D{X: 8.5, Y: 5.5}, false) {
+		t.Error("expected the fade margin to be threatened")
+	}
+	if tm.IsThreatened(api.Point2D{X: 9.5, Y: 5.5}, false) || tm.IsThreatened(api.Point2D{X: 5.5, Y: 5.5}, true) {
+		t.Error("expected no threat outside the margin or to air units")
+	}
+}
+
+func TestSafestPoint(t *testing.T) {
+	tm := newTestThreatMap(16, 16)
+	tm.add(tm.ground, api.Point2D{X: 5.5, Y: 5.5}, 2, 10)
+	tm.add(tm.air, api.Point2D{X: 5.5, Y: 5.5}, 2, 10)
+
+	// The closest safe cell is blocked for ground units, so the next closest one is used
+	tm.pathable.Set(9, 5, false)
+	if p := tm.SafestPoint(api.Point2D{X: 8.5, Y: 5.5}, 3, false); p != (api.Point2D{X: 9.5, Y: 4.5}) {
+		t.Errorf("got ground point %v", p)
+	}
+	if p := tm.SafestPoint(api.Point2D{X: 8.5, Y: 5.5}, 3, true); p != (api.Point2D{X: 9.5, Y: 5.5}) {
+		t.Errorf("got air point %v", p)
+	}
+
+	// Already safe
+	if p := tm.SafestPoint(api.Point2D{X: 12.5, Y: 12.5}, 3, false); p != (api.Point2D{X: 12.5, Y: 12.5}) {
+		t.Errorf("got point %v, expected to stay put", p)
+	}
+}
+
+func TestSafestPathCost(t *testing.T) {
+	tm := newTestThreatMap(8, 8)
+	expect := func(from, to api.Point2D, cost float32, ok bool) {
+		t.Helper()
+		c, found := tm.SafestPathCost(from, to, false)
+		if found != ok || math.Abs(float64(c-cost)) > 0.001 {
+			t.Errorf("%v to %v: got %v %v, expected %v %v", from, to, c, found, cost, ok)
+		}
+	}
+
+	// Unthreatened paths cost their length
+	expect(api.Point2D{X: 0.5, Y: 0.5}, api.Point2D{X: 3.5, Y: 0.5}, 3, true)
+	expect(api.Point2D{X: 0.5, Y: 0.5}, api.Point2D{X: 2.5, Y: 2.5}, 2*math.Sqrt2, true)
+
+	// No cutting corners around a blocked cell
+	tm.pathable.Set(1, 0, false)
+	expect(api.Point2D{X: 0.5, Y: 0.5}, api.Point2D{X: 1.5, Y: 1.5}, 2, true)
+
+	// Threatened cells are avoided when there is a way around
+	tm.ground[0*8+3] = 10
+	expect(api.Point2D{X: 2.5, Y: 0.5}, api.Point2D{X: 4.5, Y: 0.5}, 2*math.Sqrt2, true)
+
+	// Walled off
+	for y := int32(0); y < 8; y++ {
+		tm.pathable.Set(6, y, false)
+	}
+	expect(api.Point2D{X: 0.5, Y: 0.5}, api.Point2D{X: 7.5, Y: 0.5}, 0, false)
+	if _, ok := tm.SafestPathCost(api.Point2D{X: 0.5, Y: 0.5}, api.Point2D{X: 7.5, Y: 0.5}, true); !ok {
+		t.Error("expected air units to fly over the wall")
+	}
+}
+
+func TestThreatMapMemory(t *testing.T) {
+	tm := newTestThreatMap(1, 1)
+	for _, c := range []struct {
+		age    uint32
+		weight float32
+	}{{0, 1}, {112, 0.5}, {224, 0}, {1000, 0}} {
+		if w := tm.memoryWeight(c.age); w != c.weight {
+			t.Errorf("age %v: got weight %v, expected %v", c.age, w, c.weight)
+		}
+	}
+	tm.Expire = 0
+	if w := tm.memoryWeight(1000); w != 1 {
+		t.Errorf("got weight %v, expected units to never fade", w)
+	}
+}
+
+func TestPathingBits(t *testing.T) {
+	size := &api.Size2DI{X: 4, Y: 1}
+	bits := pathingBits(&api.ImageData{BitsPerPixel: 8, Size_: size, Data: []byte{0, 255, 1, 0}})
+	if bits.Width() != 4 || bits.Get(0, 0) || !bits.Get(1, 0) || !bits.Get(2, 0) || bits.Get(3, 0) {
+		t.Errorf("got an unexpected 8bpp grid")
+	}
+	if bits := pathingBits(&api.ImageData{BitsPerPixel: 1, Size_: size, Data: []byte{0x40}}); !bits.Get(1, 0) || bits.Get(0, 0) {
+		t.Errorf("got an unexpected 1bpp grid")
+	}
+	if bits := pathingBits(nil); bits.Width() != 0 {
+		t.Errorf("expected an empty grid")
+	}
+}
@@ -0,0 +1,201 @@
+package search
+
+import (
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/botutil"
+	"github.com/chippydip/go-sc2ai/enums/ability"
+)
+
+// WorkerManager keeps workers spread across our bases as part of Map.Update. It moves workers
+// off oversaturated bases, keeps the requested number of workers on gas and sends workers that
+// no base needs to mine at the nearest free base. Workers passed to MarkWorkerAsUsed are left
+// alone until the next update.
+type WorkerManager struct {
+	m            *Map
+	gasTarget    int
+	longDistance map[api.UnitTag]api.UnitTag // worker -> mineral patch
+}
+
+// NewWorkerManager creates a worker manager and attaches it to the map. By default every
+// finished gas building is saturated.
+func NewWorkerManager(m *Map) *WorkerManager {
+	wm := &WorkerManager{
+		m:            m,
+		gasTarget:    -1,
+		longDistance: map[api.UnitTag]api.UnitTag{},
+	}
+	m.manager = wm
+	return wm
+}
+
+// SetGasTarget sets the total number of workers that should mine gas across all bases. Negative
+// values saturate every finished gas building.
+func (wm *WorkerManager) SetGasTarget(workers int) {
+	wm.gasTarget = workers
+}
+
+// GasTarget returns the number of gas workers requested with SetGasTarget.
+func (wm *WorkerManager) GasTarget() int {
+	return wm.gasTarget
+}
+
+// LongDistanceWorkers returns the number of workers mining at bases without a town hall.
+func (wm *WorkerManager) LongDistanceWorkers() int {
+	return len(wm.longDistance)
+}
+
+func (wm *WorkerManager) distribute(bot *botutil.Bot) {
+	wm.balanceGas()
+	wm.balanceBases()
+	wm.mineLongDistance(bot)
+}
+
+// balanceGas moves workers between minerals and gas to reach the gas target.
+func (wm *WorkerManager) balanceGas() {
+	current, capacity := 0, 0
+	for _, base := range wm.m.Bases {
+		for _, geyser := range base.finishedGeysers() {
+			current += len(base.minedBy[geyser.Tag])
+			capacity += 3
+		}
+	}
+	target := capacity
+	if wm.gasTarget >= 0 && wm.gasTarget < capacity {
+		target = wm.gasTarget
+	}
+
+	for _, base := range wm.m.Bases {
+		for _, geyser := range base.finishedGeysers() {
+			for current > target && len(base.minedBy[geyser.Tag]) > 0 {
+				worker := base.gasWorker(geyser)
+				if worker.IsNil() {
+					break // all inside the building, try again next step
+				}
+				to := base
+				if !to.hasMineralRoom() {
+					to = wm.m.NearestBaseIf(base.Location, func(b *Base) bool {
+						return b.IsFinished() && b.hasMineralRoom()
+					})
+				}
+				if to == nil {
+					break // no minerals to mine, stay on gas
+				}
+				base.RemoveWorker(worker)
+				to.addMineralWorker(worker)
+				current--
+			}
+			for current < target && len(base.minedBy[geyser.Tag]) < 3 {
+				worker := base.PeakWorker()
+				if worker.IsNil() {
+					break
+				}
+				base.RemoveWorker(worker)
+				base.addGasWorker(worker)
+				current++
+			}
+		}
+	}
+}
+
+// balanceBases moves mineral workers from oversaturated bases to the nearest finished base that
+// still needs workers.
+func (wm *WorkerManager) balanceBases() {
+	for _, from := range wm.m.Bases {
+		for from.IsOverSaturated() {
+			to := wm.m.NearestBaseIf(from.Location, func(b *Base) bool {
+				return b != from && b.NeedsWorker(false) && b.hasMineralRoom()
+			})
+			worker := from.PeakWorker()
+			if to == nil || worker.IsNil() {
+				break
+			}
+			wm.m.bot.Logger().Debug("Moving over saturated worker", "worker", worker.Tag, "from", from.i, "to", to.i)
+			from.RemoveWorker(worker)
+			to.addMineralWorker(worker)
+		}
+	}
+}
+
+// mineLongDistance sends workers that no base wants to the closest base with minerals that
+// nobody owns. Those workers are picked up again automatically once a base has room for them.
+func (wm *WorkerManager) mineLongDistance(bot *botutil.Bot) {
+	// Forget workers that died, were used or were assigned to a base again
+	free := map[api.UnitTag]bool{}
+	for _, worker := range wm.m.freeWorkers {
+		free[worker.Tag] = true
+	}
+	for worker := range wm.longDistance {
+		if !free[worker] || wm.m.usedWorkers[worker] {
+			delete(wm.longDistance, worker)
+		}
+	}
+
+	miners := map[api.UnitTag]int{}
+	for _, patch := range wm.longDistance {
+		miners[patch]++
+	}
+
+	for _, worker := range wm.m.freeWorkers {
+		patchTag, ok := wm.longDistance[worker.Tag]
+		if !ok {
+			base := wm.m.NearestBaseIf(worker.Pos2D(), func(b *Base) bool {
+				return b.TownHall.IsNil() && len(b.Minerals) > 0
+			})
+			if base == nil {
+				bot.Logger().Debug("Nothing to do with free worker", "worker", worker.Tag)
+				continue
+			}
+			patch := base.Minerals[0]
+			for _, m := range base.Minerals[1:] {
+				if miners[m.Tag] < miners[patch.Tag] {
+					patch = m
+				}
+			}
+			patchTag = patch.Tag
+			wm.longDistance[worker.Tag] = patchTag
+			miners[patchTag]++
+		}
+
+		if !worker.IsIdle() && (ability.Remap(worker.Orders[0].AbilityId) != ability.Harvest_Gather ||
+			worker.Orders[0].GetTargetUnitTag() == patchTag) {
+			continue // already on its way or returning cargo
+		}
+		if patch := bot.UnitByTag(patchTag); !patch.IsNil() {
+			bot.UnitOrderTarget(worker, ability.Harvest_Gather, patch)
+		} else {
+			delete(wm.longDistance, worker.Tag) // mined out, pick another next time
+		}
+	}
+}
+
+// finishedGeysers returns the geysers with a finished gas building of our own.
+func (base *Base) finishedGeysers() []botutil.Unit {
+	var geysers []botutil.Unit
+	for _, geyser := range base.Geysers {
+		building := base.GasBuildings[geyser.Pos2D()]
+		if !building.IsNil() && building.Alliance == api.Alliance_Self && building.BuildProgress == 1.0 {
+			geysers = append(geysers, geyser)
+		}
+	}
+	return geysers
+}
+
+// hasMineralRoom returns true if a mineral patch has room for another worker.
+func (base *Base) hasMineralRoom() bool {
+	for _, mineral := range base.Minerals {
+		if len(base.minedBy[mineral.Tag]) < 3 {
+			return true
+		}
+	}
+	return false
+}
+
+// gasWorker returns one of the visible workers assigned to the geyser.
+func (base *Base) gasWorker(geyser botutil.Unit) botutil.Unit {
+	for tag := range base.minedBy[geyser.Tag] {
+		if worker, ok := base.Workers[tag]; ok {
+			return worker
+		}
+	}
+	return botutil.Unit{}
+}
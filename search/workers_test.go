@@ -0,0 +1,97 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/chippydip/go-sc2ai/api"
+	"github.com/chippydip/go-sc2ai/enums/terran"
+)
+
+// gasWorkers returns the number of workers assigned to gas at the base.
+func gasWorkers(base *Base) int {
+	n := 0
+	for _, geyser := range base.finishedGeysers() {
+		n += len(base.minedBy[geyser.Tag])
+	}
+	return n
+}
+
+func TestWorkerManagerBases(t *testing.T) {
+	w, locs := newTestWorld(2)
+	w.add(terran.CommandCenter, api.Alliance_Self, locs[0].Location.X, locs[0].Location.Y)
+	for i := 0; i < 20; i++ {
+		w.add(terran.SCV, api.Alliance_Self, locs[0].Location.X, locs[0].Location.Y+1)
+	}
+	w.start(locs, &api.Observation{})
+	NewWorkerManager(w.m)
+
+	w.step(&api.Observation{GameLoop: 1})
+	if n := w.m.Bases[0].NumWorkers(); n != 20 {
+		t.Fatalf("got %v workers at the main, expected 20", n)
+	}
+
+	// A new base takes the extra workers
+	w.add(terran.CommandCenter, api.Alliance_Self, locs[1].Location.X, locs[1].Location.Y)
+	w.step(&api.Observation{GameLoop: 2})
+	if main, natural := w.m.Bases[0].NumWorkers(), w.m.Bases[1].NumWorkers(); main != 16 || natural != 4 {
+		t.Errorf("got %v and %v workers, expected 16 and 4", main, natural)
+	}
+}
+
+func TestWorkerManagerGas(t *testing.T) {
+	w, locs := newTestWorld(2)
+	main := locs[0].Location
+	w.add(terran.CommandCenter, api.Alliance_Self, main.X, main.Y)
+	w.add(terran.Refinery, api.Alliance_Self, main.X+7, main.Y)
+	for i := 0; i < 16; i++ {
+		w.add(terran.SCV, api.Alliance_Self, main.X, main.Y+1)
+	}
+	w.start(locs, &api.Observation{})
+	wm := NewWorkerManager(w.m)
+	base := w.m.Bases[0]
+
+	w.step(&api.Observation{GameLoop: 1})
+	if gas, n := gasWorkers(base), base.NumWorkers(); gas != 3 || n != 16 {
+		t.Fatalf("got %v gas workers out of %v, expected 3 out of 16", gas, n)
+	}
+
+	wm.SetGasTarget(1)
+	w.step(&api.Observation{GameLoop: 2})
+	if gas, n := gasWorkers(base), base.NumWorkers(); gas != 1 || n != 16 {
+		t.Errorf("got %v gas workers out of %v, expected 1 out of 16", gas, n)
+	}
+
+	wm.SetGasTarget(-1)
+	w.step(&api.Observation{GameLoop: 3})
+	if gas, n := gasWorkers(base), base.NumWorkers(); gas != 3 || n != 16 {
+		t.Errorf("got %v gas workers out of %v, expected 3 out of 16", gas, n)
+	}
+}
+
+func TestWorkerManagerGasFullMinerals(t *testing.T) {
+	w, locs := newTestWorld(2)
+	main := locs[0].Location
+	w.add(terran.CommandCenter, api.Alliance_Self, main.X, main.Y)
+	w.add(terran.Refinery, api.Alliance_Self, main.X+7, main.Y)
+	for i := 0; i < 27; i++ {
+		w.add(terran.SCV, api.Alliance_Self, main.X, main.Y+1)
+	}
+	w.start(locs, &api.Observation{})
+	wm := NewWorkerManager(w.m)
+	w.step(&api.Observation{GameLoop: 1})
+
+	// With every patch full the gas workers have nowhere to go
+	wm.SetGasTarget(0)
+	w.step(&api.Observation{GameLoop: 2})
+	if gas, n := gasWorkers(w.m.Bases[0]), w.m.Bases[0].NumWorkers(); gas != 3 || n != 27 {
+		t.Errorf("got %v gas workers out of %v, expected 3 out of 27", gas, n)
+	}
+
+	// Until a new base has room for them
+	w.add(terran.CommandCenter, api.Alliance_Self, locs[1].Location.X, locs[1].Location.Y)
+	w.step(&api.Observation{GameLoop: 3})
+	total := w.m.Bases[0].NumWorkers() + w.m.Bases[1].NumWorkers()
+	if gas := gasWorkers(w.m.Bases[0]); gas != 0 || total != 27 {
+		t.Errorf("got %v gas workers and %v assigned, expected 0 and 27", gas, total)
+	}
+}